@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yourname/go-trendboard/internal/logger"
+	"github.com/yourname/go-trendboard/internal/usecase"
+)
+
+func init() {
+	// compact command
+	var compactCmd = &cobra.Command{
+		Use:   "compact",
+		Short: "Roll old daily snapshots into monthly compressed archives",
+		Long: `Move the daily data files older than daily_retention_days into monthly archives in
+data/archive (gzip-compressed JSON Lines, one snapshot per line), which are read like the daily
+files. With archive_granularity set to weekly, only the last snapshot of each week is kept
+among them. Only the file storage backend supports compaction.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			log := logger.NewLogger(cfg)
+			storer, err := newStorer(cfg, log)
+			if err != nil {
+				return err
+			}
+			uc := usecase.NewUsecase(cfg, log, nil, storer) // Fetcher is not needed for compact
+
+			report, err := uc.Compact(cmd.Context())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Archived %d snapshot(s), dropped %d.\n", report.Archived, report.Dropped)
+			return nil
+		},
+	}
+
+	rootCmd.AddCommand(compactCmd)
+}
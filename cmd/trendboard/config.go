@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/yourname/go-trendboard/internal/infra/storage"
+	"github.com/yourname/go-trendboard/internal/logger"
+	"github.com/yourname/go-trendboard/internal/usecase"
+)
+
+func init() {
+	var configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Inspect and validate the configuration",
+	}
+
+	// config show command
+	var showCmd = &cobra.Command{
+		Use:   "show",
+		Short: "Show the effective configuration and where each value comes from",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			configFile := cfg.ConfigFileUsed()
+			if configFile == "" {
+				configFile = "(none)"
+			}
+			fmt.Fprintf(out, "Config file: %s\n", configFile)
+			if profile := cfg.ProfileUsed(); profile != "" {
+				fmt.Fprintf(out, "Profile: %s\n", profile)
+			}
+			fmt.Fprintln(out)
+
+			w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+			for _, s := range cfg.Settings() {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", s.Key, s.Value, s.Source)
+			}
+			return w.Flush()
+		},
+	}
+
+	// config validate command
+	var validateCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Check that the configuration is usable",
+		Long: `Check that settings hold known values, the data and dashboard directories
+are writable, the HTML template parses and the repositories file is valid.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			log := logger.NewLogger(cfg)
+			storer := storage.NewFileStorer(cfg, log)
+			uc := usecase.NewUsecase(cfg, log, nil, storer) // Fetcher is not needed for validation
+
+			if err := uc.ValidateConfig(cmd.Context()); err != nil {
+				return fmt.Errorf("invalid config:\n%w", err)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), "Configuration is valid.")
+			return nil
+		},
+	}
+
+	configCmd.AddCommand(showCmd, validateCmd)
+	rootCmd.AddCommand(configCmd)
+}
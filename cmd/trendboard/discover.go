@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/yourname/go-trendboard/internal/infra/github"
+	"github.com/yourname/go-trendboard/internal/infra/storage"
+	"github.com/yourname/go-trendboard/internal/logger"
+	"github.com/yourname/go-trendboard/internal/usecase"
+)
+
+func init() {
+	// discover command
+	var discoverCmd = &cobra.Command{
+		Use:   "discover",
+		Short: "Find repositories to track with GitHub search",
+		Long: `Run the configured GitHub search queries (DISCOVER_QUERIES) and list the
+repositories found that aren't tracked yet. With --add, they are appended to the
+repository list with the "discovered" tag.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			if err := cfg.ValidateGitHubAuth(); err != nil {
+				return fmt.Errorf("invalid config: %w", err)
+			}
+			log := logger.NewLogger(cfg)
+			// Searching is only supported by the REST client.
+			client, err := github.NewClient(cfg, log)
+			if err != nil {
+				return fmt.Errorf("failed to create GitHub client: %w", err)
+			}
+			storer := storage.NewFileStorer(cfg, log)
+			uc := usecase.NewUsecase(cfg, log, client, storer)
+
+			add, _ := cmd.Flags().GetBool("add")
+			candidates, added, err := uc.Discover(cmd.Context(), add)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(candidates) == 0 {
+				fmt.Fprintln(out, "No new repositories found.")
+				return nil
+			}
+			w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tSTARS")
+			for _, repo := range candidates {
+				fmt.Fprintf(w, "%s\t%d\n", repo.FullName, repo.Stars)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			if add {
+				fmt.Fprintf(out, "\nAdded %d repositories with the %q tag.\n", len(added), usecase.DiscoveredTag)
+			}
+			return nil
+		},
+	}
+	discoverCmd.Flags().Bool("add", false, "append the discovered repositories to the repository list")
+
+	rootCmd.AddCommand(discoverCmd)
+}
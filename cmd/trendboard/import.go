@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yourname/go-trendboard/internal/infra/storage"
+	"github.com/yourname/go-trendboard/internal/logger"
+	"github.com/yourname/go-trendboard/internal/usecase"
+)
+
+func init() {
+	// import command
+	var importCmd = &cobra.Command{
+		Use:   "import FILE.md",
+		Short: "Import repositories linked from a Markdown file",
+		Long: `Parse the GitHub repository links of a Markdown file, such as the awesome-go
+README, and merge them into the repository list. Repositories that are already
+tracked and links that don't point to a repository are reported.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			log := logger.NewLogger(cfg)
+			storer := storage.NewFileStorer(cfg, log)
+			uc := usecase.NewUsecase(cfg, log, nil, storer) // Fetcher is not needed for import
+
+			tags, _ := cmd.Flags().GetStringSlice("tag")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			report, err := uc.ImportRepos(cmd.Context(), args[0], tags, dryRun)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			verb := "added"
+			if dryRun {
+				verb = "would add"
+			}
+			for _, name := range report.Added {
+				fmt.Fprintf(out, "%s %s\n", verb, name)
+			}
+			for _, name := range report.Duplicates {
+				fmt.Fprintf(out, "duplicate %s\n", name)
+			}
+			for _, link := range report.Invalid {
+				fmt.Fprintf(out, "invalid %s\n", link)
+			}
+			fmt.Fprintf(out, "\n%d new, %d duplicate, %d invalid\n", len(report.Added), len(report.Duplicates), len(report.Invalid))
+			return nil
+		},
+	}
+	importCmd.Flags().StringSlice("tag", nil, "tags (categories) to give the imported repositories")
+	importCmd.Flags().Bool("dry-run", false, "report what would be imported without changing the repository list")
+
+	rootCmd.AddCommand(importCmd)
+}
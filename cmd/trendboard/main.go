@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 
 	"github.com/spf13/cobra"
@@ -11,6 +13,7 @@ import (
 	"github.com/yourname/go-trendboard/internal/infra/storage"
 	"github.com/yourname/go-trendboard/internal/logger"
 	"github.com/yourname/go-trendboard/internal/usecase"
+	"github.com/yourname/go-trendboard/internal/version"
 )
 
 var rootCmd = &cobra.Command{
@@ -20,6 +23,7 @@ var rootCmd = &cobra.Command{
 of specified Go open-source software from GitHub and generates a static dashboard
 in Markdown or HTML format.`,
 	SilenceUsage: true, // Prevents usage from being displayed on error
+	Version:      version.Current(),
 }
 
 func init() {
@@ -28,7 +32,7 @@ func init() {
 		Use:   "init",
 		Short: "Initialize a default repos.json file",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, err := config.Load()
+			cfg, err := loadConfig(cmd)
 			if err != nil {
 				return fmt.Errorf("failed to load config: %w", err)
 			}
@@ -45,13 +49,19 @@ func init() {
 		Use:   "update",
 		Short: "Fetch the latest star counts from GitHub",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, err := config.Load()
+			cfg, err := loadConfig(cmd)
 			if err != nil {
 				return fmt.Errorf("failed to load config: %w", err)
 			}
 			log := logger.NewLogger(cfg)
-			fetcher := github.NewClient(cfg, log)
-			storer := storage.NewFileStorer(cfg, log)
+			fetcher, err := newFetcher(cfg, log)
+			if err != nil {
+				return err
+			}
+			storer, err := newStorer(cfg, log)
+			if err != nil {
+				return err
+			}
 			uc := usecase.NewUsecase(cfg, log, fetcher, storer)
 
 			return uc.Update(cmd.Context())
@@ -63,24 +73,76 @@ func init() {
 		Use:   "generate",
 		Short: "Generate the trend dashboard",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, err := config.Load()
+			cfg, err := loadConfig(cmd)
 			if err != nil {
 				return fmt.Errorf("failed to load config: %w", err)
 			}
 			log := logger.NewLogger(cfg)
-			storer := storage.NewFileStorer(cfg, log)
+			storer, err := newStorer(cfg, log)
+			if err != nil {
+				return err
+			}
 			uc := usecase.NewUsecase(cfg, log, nil, storer) // Fetcher is not needed for generate
 
 			return uc.Generate(cmd.Context())
 		},
 	}
 
+	rootCmd.PersistentFlags().String("config", os.Getenv("TRENDBOARD_CONFIG"), "config file (default is ./trendboard.yaml, then $XDG_CONFIG_HOME/trendboard/trendboard.yaml)")
+	rootCmd.PersistentFlags().String("profile", os.Getenv("TRENDBOARD_PROFILE"), "name of the config file profile to apply")
+	config.BindFlags(rootCmd.PersistentFlags())
+
 	rootCmd.AddCommand(initCmd, updateCmd, generateCmd)
 }
 
+// loadConfig loads the configuration for cmd from the config file, profile and flags given on the command line.
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	flags := cmd.Flags()
+	configFile, _ := flags.GetString("config")
+	profile, _ := flags.GetString("profile")
+
+	return config.LoadWithOptions(config.Options{
+		ConfigFile: configFile,
+		Profile:    profile,
+		Flags:      flags,
+	})
+}
+
+// newFetcher validates the GitHub API settings and creates the fetcher for commands that access GitHub.
+func newFetcher(cfg *config.Config, log *slog.Logger) (github.Fetcher, error) {
+	if err := cfg.ValidateGitHubAuth(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	fetcher, err := github.NewFetcher(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fetcher: %w", err)
+	}
+	return fetcher, nil
+}
+
+// closers holds the resources opened by the command, released once it has run.
+var closers []io.Closer
+
+// newStorer creates the storer of the configured storage backend for commands that read or
+// write trend data. Commands that only use the repositories file use a FileStorer directly.
+func newStorer(cfg *config.Config, log *slog.Logger) (storage.Storer, error) {
+	storer, err := storage.NewStorer(cfg, log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storer: %w", err)
+	}
+	if closer, ok := storer.(io.Closer); ok {
+		closers = append(closers, closer)
+	}
+	return storer, nil
+}
+
 func main() {
 	ctx := context.Background()
-	if err := rootCmd.ExecuteContext(ctx); err != nil {
+	err := rootCmd.ExecuteContext(ctx)
+	for _, closer := range closers {
+		closer.Close()
+	}
+	if err != nil {
 		// The error is already logged by cobra, so we just exit.
 		os.Exit(1)
 	}
@@ -23,8 +23,8 @@ func TestInitCmd(t *testing.T) {
 	// Restore original working directory at the end of the test
 	defer os.Chdir(originalWd)
 
-	// Set a dummy token for the test, as config loading requires it.
-	t.Setenv("GITHUB_TOKEN", "dummy-token-for-test")
+	// 'init' never touches the network, so it must work without a token.
+	t.Setenv("GITHUB_TOKEN", "")
 
 	// Redirect stdout to a buffer to capture output if needed
 	var out bytes.Buffer
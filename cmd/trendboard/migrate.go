@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yourname/go-trendboard/internal/infra/github"
+	"github.com/yourname/go-trendboard/internal/infra/storage"
+	"github.com/yourname/go-trendboard/internal/logger"
+	"github.com/yourname/go-trendboard/internal/usecase"
+)
+
+func init() {
+	var migrateCmd = &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate saved data to newer formats",
+	}
+
+	// migrate ids command
+	var idsCmd = &cobra.Command{
+		Use:   "ids",
+		Short: "Record GitHub repository IDs in snapshots saved before IDs were recorded",
+		Long: `Fill in the numeric GitHub ID of the repositories in saved snapshots, so that they are
+matched by ID rather than by name. IDs are taken from newer snapshots of the same repository,
+following recorded renames; the remaining ones are looked up on GitHub unless --offline is set.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			log := logger.NewLogger(cfg)
+			offline, _ := cmd.Flags().GetBool("offline")
+			var fetcher github.Fetcher
+			if !offline {
+				if fetcher, err = newFetcher(cfg, log); err != nil {
+					return err
+				}
+			}
+			storer, err := newStorer(cfg, log)
+			if err != nil {
+				return err
+			}
+			uc := usecase.NewUsecase(cfg, log, fetcher, storer)
+
+			report, err := uc.MigrateIDs(cmd.Context(), !offline)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Updated %d of %d snapshot(s).\n", report.Updated, report.Snapshots)
+			for _, name := range report.Unresolved {
+				fmt.Fprintf(out, "unresolved %s\n", name)
+			}
+			return nil
+		},
+	}
+	idsCmd.Flags().Bool("offline", false, "only use IDs found in other snapshots, without looking them up on GitHub")
+
+	// migrate sqlite command
+	var sqliteCmd = &cobra.Command{
+		Use:   "sqlite",
+		Short: "Import the JSON data files into the SQLite database",
+		Long: `Copy every daily JSON file of the data directory and the recorded renames into the
+SQLite database at sqlite_path, replacing snapshots of the same dates. Set storage_backend
+to sqlite afterwards to use the database. The JSON files are left untouched.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			log := logger.NewLogger(cfg)
+			db, err := storage.NewSQLiteStorer(cfg, log)
+			if err != nil {
+				return fmt.Errorf("failed to open database: %w", err)
+			}
+			defer db.Close()
+			uc := usecase.NewUsecase(cfg, log, nil, storage.NewFileStorer(cfg, log)) // Fetcher is not needed for migration
+
+			copied, err := uc.CopyData(cmd.Context(), db)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Imported %d snapshot(s) into %s.\n", copied, cfg.SQLitePath)
+			return nil
+		},
+	}
+
+	// migrate schema command
+	var schemaCmd = &cobra.Command{
+		Use:   "schema",
+		Short: "Upgrade the daily data files to the current format",
+		Long: `Rewrite the daily JSON files saved in the legacy format, a bare array of repositories,
+in the current format, which records the schema version and the metadata of each snapshot.
+Files in the legacy format are still read, so upgrading is optional. The SQLite database
+upgrades its schema on its own when it is opened.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			log := logger.NewLogger(cfg)
+			storer, err := newStorer(cfg, log)
+			if err != nil {
+				return err
+			}
+			uc := usecase.NewUsecase(cfg, log, nil, storer) // Fetcher is not needed for migration
+
+			upgraded, err := uc.MigrateSchema(cmd.Context())
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Upgraded %d snapshot(s).\n", upgraded)
+			return nil
+		},
+	}
+
+	migrateCmd.AddCommand(idsCmd, sqliteCmd, schemaCmd)
+	rootCmd.AddCommand(migrateCmd)
+}
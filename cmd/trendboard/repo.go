@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/yourname/go-trendboard/internal/infra/github"
+	"github.com/yourname/go-trendboard/internal/logger"
+	"github.com/yourname/go-trendboard/internal/usecase"
+)
+
+func init() {
+	var repoCmd = &cobra.Command{
+		Use:   "repo",
+		Short: "Manage the list of tracked repositories",
+	}
+
+	// repo add command
+	var addCmd = &cobra.Command{
+		Use:   "add OWNER/NAME...",
+		Short: "Add repositories to the tracked list",
+		Long: `Add repositories to the tracked list. Names are normalized to lower-case
+"owner/name"; GitHub URLs are accepted too. Already tracked repositories are skipped.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			verify, _ := cmd.Flags().GetBool("verify")
+			tags, _ := cmd.Flags().GetStringSlice("tag")
+			uc, err := newRepoUsecase(cmd, verify)
+			if err != nil {
+				return err
+			}
+
+			added, err := uc.AddRepos(cmd.Context(), args, tags, verify)
+			if err != nil {
+				return err
+			}
+			for _, name := range added {
+				fmt.Fprintf(cmd.OutOrStdout(), "added %s\n", name)
+			}
+			return nil
+		},
+	}
+	addCmd.Flags().Bool("verify", false, "check that each repository exists on GitHub before adding it")
+	addCmd.Flags().StringSlice("tag", nil, "tags (categories) to give the added repositories")
+
+	// repo remove command
+	var removeCmd = &cobra.Command{
+		Use:     "remove OWNER/NAME...",
+		Aliases: []string{"rm"},
+		Short:   "Remove repositories from the tracked list",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			uc, err := newRepoUsecase(cmd, false)
+			if err != nil {
+				return err
+			}
+
+			removed, err := uc.RemoveRepos(cmd.Context(), args)
+			if err != nil {
+				return err
+			}
+			for _, name := range removed {
+				fmt.Fprintf(cmd.OutOrStdout(), "removed %s\n", name)
+			}
+			return nil
+		},
+	}
+
+	// repo list command
+	var listCmd = &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "List the tracked repositories",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			uc, err := newRepoUsecase(cmd, false)
+			if err != nil {
+				return err
+			}
+
+			targets, err := uc.ListRepos(cmd.Context())
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "NAME\tDISPLAY NAME\tTAGS\tSTATUS")
+			for _, target := range targets {
+				status := "enabled"
+				if target.Disabled {
+					status = "disabled"
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", target.Name, target.DisplayName, strings.Join(target.Tags, ","), status)
+			}
+			return w.Flush()
+		},
+	}
+
+	// repo check command
+	var checkCmd = &cobra.Command{
+		Use:   "check",
+		Short: "Report invalid, duplicate and non-normalized entries of the tracked list",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			verify, _ := cmd.Flags().GetBool("verify")
+			uc, err := newRepoUsecase(cmd, verify)
+			if err != nil {
+				return err
+			}
+
+			issues, err := uc.CheckRepos(cmd.Context(), verify)
+			if err != nil {
+				return err
+			}
+			if len(issues) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "All repositories are valid.")
+				return nil
+			}
+			for _, issue := range issues {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", issue.RepoName, issue.Problem)
+			}
+			return fmt.Errorf("found %d problem(s) in the repositories file", len(issues))
+		},
+	}
+	checkCmd.Flags().Bool("verify", false, "also check that each repository exists on GitHub")
+
+	// repo renames command
+	var renamesCmd = &cobra.Command{
+		Use:   "renames",
+		Short: "Show tracked repositories that were renamed or transferred on GitHub",
+		Long: `Show the entries of the tracked list that name a repository which 'update' found
+renamed or transferred on GitHub. With --apply, the entries are rewritten to the new
+names, keeping their metadata; entries whose new name is already tracked are removed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			apply, _ := cmd.Flags().GetBool("apply")
+			uc, err := newRepoUsecase(cmd, false)
+			if err != nil {
+				return err
+			}
+
+			renames, err := uc.ApplyRenames(cmd.Context(), !apply)
+			if err != nil {
+				return err
+			}
+			if len(renames) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "No tracked repository was renamed.")
+				return nil
+			}
+			for _, rename := range renames {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s -> %s\n", rename.From, rename.To)
+			}
+			if !apply {
+				fmt.Fprintln(cmd.OutOrStdout(), "\nRun with --apply to update the repositories file.")
+			}
+			return nil
+		},
+	}
+	renamesCmd.Flags().Bool("apply", false, "rewrite the repositories file with the new names")
+
+	repoCmd.AddCommand(addCmd, removeCmd, listCmd, checkCmd, renamesCmd)
+	rootCmd.AddCommand(repoCmd)
+}
+
+// newRepoUsecase creates the usecase for the repo commands. A fetcher is only
+// created when repositories are verified on GitHub.
+func newRepoUsecase(cmd *cobra.Command, verify bool) (*usecase.Usecase, error) {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	log := logger.NewLogger(cfg)
+	var fetcher github.Fetcher
+	if verify {
+		if fetcher, err = newFetcher(cfg, log); err != nil {
+			return nil, err
+		}
+	}
+	storer, err := newStorer(cfg, log)
+	if err != nil {
+		return nil, err
+	}
+	return usecase.NewUsecase(cfg, log, fetcher, storer), nil
+}
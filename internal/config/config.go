@@ -1,9 +1,16 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
@@ -12,6 +19,29 @@ type Config struct {
 	// GitHubToken is the token for authenticating with the GitHub API.
 	GitHubToken string `mapstructure:"github_token"`
 
+	// GitHubBaseURL is the REST API base URL of a GitHub Enterprise Server instance.
+	// The public GitHub API is used when it is empty.
+	GitHubBaseURL string `mapstructure:"github_base_url"`
+
+	// GitHubUploadURL is the upload API URL of a GitHub Enterprise Server instance.
+	// It defaults to GitHubBaseURL.
+	GitHubUploadURL string `mapstructure:"github_upload_url"`
+
+	// GitHubTokens are additional tokens that are rotated together with GitHubToken.
+	GitHubTokens []string `mapstructure:"github_tokens"`
+
+	// GitHubTokenStrategy selects how tokens are picked from the pool (round-robin or most-remaining).
+	GitHubTokenStrategy string `mapstructure:"github_token_strategy"`
+
+	// GitHubAppID is the ID of the GitHub App to authenticate as, instead of using GitHubToken.
+	GitHubAppID int64 `mapstructure:"github_app_id"`
+
+	// GitHubAppInstallationID is the ID of the GitHub App installation to mint tokens for.
+	GitHubAppInstallationID int64 `mapstructure:"github_app_installation_id"`
+
+	// GitHubAppPrivateKeyPath is the path to the GitHub App private key in PEM format.
+	GitHubAppPrivateKeyPath string `mapstructure:"github_app_private_key_path"`
+
 	// LogLevel is the logging level (e.g., debug, info, warn, error).
 	LogLevel string `mapstructure:"log_level"`
 
@@ -21,6 +51,12 @@ type Config struct {
 	// DataDirPath is the path to the directory where daily trend data is stored.
 	DataDirPath string `mapstructure:"data_dir_path"`
 
+	// StorageBackend selects where trend data is stored: one JSON file per day (file) or a SQLite database (sqlite).
+	StorageBackend string `mapstructure:"storage_backend"`
+
+	// SQLitePath is the path to the SQLite database used by the sqlite storage backend.
+	SQLitePath string `mapstructure:"sqlite_path"`
+
 	// DashboardFilePath is the path where the generated dashboard file will be saved.
 	DashboardFilePath string `mapstructure:"dashboard_file_path"`
 
@@ -29,10 +65,102 @@ type Config struct {
 
 	// DashboardTemplatePath is the path to the HTML template file.
 	DashboardTemplatePath string `mapstructure:"dashboard_template_path"`
+
+	// Fetcher selects the GitHub API used to fetch star counts (rest or graphql).
+	Fetcher string `mapstructure:"fetcher"`
+
+	// GraphQLBatchSize is the number of repositories fetched per GraphQL query.
+	GraphQLBatchSize int `mapstructure:"graphql_batch_size"`
+
+	// RateLimitThreshold is the remaining quota below which requests are spread until the rate limit resets.
+	RateLimitThreshold int `mapstructure:"rate_limit_threshold"`
+
+	// RateLimitWait enables sleeping until the rate limit resets once the quota is exhausted.
+	RateLimitWait bool `mapstructure:"rate_limit_wait"`
+
+	// RateLimitMaxWait is the longest time to wait for a rate limit to reset before giving up.
+	RateLimitMaxWait time.Duration `mapstructure:"rate_limit_max_wait"`
+
+	// RetryMaxAttempts is the total number of attempts for a fetch that fails with a transient error.
+	RetryMaxAttempts int `mapstructure:"retry_max_attempts"`
+
+	// RetryInitialBackoff is the delay before the first retry; it doubles on every further retry.
+	RetryInitialBackoff time.Duration `mapstructure:"retry_initial_backoff"`
+
+	// RetryMaxBackoff caps the delay between two attempts.
+	RetryMaxBackoff time.Duration `mapstructure:"retry_max_backoff"`
+
+	// RetryJitter is the fraction (0 to 1) of each retry delay that is randomised.
+	RetryJitter float64 `mapstructure:"retry_jitter"`
+
+	// CarryForward records the last known star count, marked as stale, for repositories that fail to fetch.
+	CarryForward bool `mapstructure:"carry_forward"`
+
+	// CarryForwardMaxDays is how many days back to look for the last known star count.
+	CarryForwardMaxDays int `mapstructure:"carry_forward_max_days"`
+
+	// IntradaySamples keeps every star count fetched during a day in the day's snapshot, in addition
+	// to the latest one, for hourly granularity when update runs several times a day.
+	IntradaySamples bool `mapstructure:"intraday_samples"`
+
+	// DailyRetentionDays is for how many days snapshots are kept as daily files;
+	// compact moves older ones into monthly archives.
+	DailyRetentionDays int `mapstructure:"daily_retention_days"`
+
+	// ArchiveGranularity is which archived snapshots compact keeps: "daily" keeps them all,
+	// "weekly" only the last one of each week.
+	ArchiveGranularity string `mapstructure:"archive_granularity"`
+
+	// HTTPCacheDir is the directory where GitHub API responses are cached for conditional requests.
+	// Caching is disabled when it is empty.
+	HTTPCacheDir string `mapstructure:"http_cache_dir"`
+
+	// IndexCacheDir is the directory where the file backend caches snapshot summaries for history queries.
+	// The user cache directory is used when it is empty.
+	IndexCacheDir string `mapstructure:"index_cache_dir"`
+
+	// DashboardCategories are the categories (repository tags) to render leaderboards for.
+	// Every category found is rendered when it is empty.
+	DashboardCategories []string `mapstructure:"dashboard_categories"`
+
+	// DiscoverQueries are the GitHub search queries run by discover.
+	// "{today-Nd}" is replaced with the date N days ago, so that queries can select recent repositories.
+	DiscoverQueries []string `mapstructure:"discover_queries"`
+
+	// DiscoverLimit is the maximum number of search results considered per discover query.
+	DiscoverLimit int `mapstructure:"discover_limit"`
+
+	// configFile is the config file that was read, if any.
+	configFile string
+	// profile is the config file profile that was applied, if any.
+	profile string
+	// effective holds every setting with its value and source, as resolved by Load.
+	effective []Setting
+}
+
+// Options controls the sources Load reads in addition to defaults and environment variables.
+type Options struct {
+	// ConfigFile is the path of the config file to read. When empty, a trendboard.yaml
+	// (or .yml/.toml) file is searched in the working directory and then in the user
+	// config directory ($XDG_CONFIG_HOME/trendboard); no config file at all is fine.
+	ConfigFile string
+
+	// Profile is the name of a profile in the config file whose settings override the top-level ones.
+	Profile string
+
+	// Flags are the command-line flags registered with BindFlags.
+	// Flags that were explicitly set take precedence over every other source.
+	Flags *pflag.FlagSet
 }
 
 // Load loads the configuration from environment variables and sets defaults.
 func Load() (*Config, error) {
+	return LoadWithOptions(Options{})
+}
+
+// LoadWithOptions loads the configuration. Sources take precedence in this order:
+// flags, environment variables, the selected profile, the config file, and defaults.
+func LoadWithOptions(opts Options) (*Config, error) {
 	v := viper.New()
 
 	// Configure viper to read from environment variables
@@ -40,30 +168,148 @@ func Load() (*Config, error) {
 	v.AutomaticEnv()
 
 	// Set default values
-	v.SetDefault("log_level", "info")
-	v.SetDefault("repos_file_path", "repos.json")
-	v.SetDefault("data_dir_path", "data")
-	v.SetDefault("dashboard_file_path", "dashboard.md")
-	v.SetDefault("dashboard_format", "md")
-	v.SetDefault("dashboard_template_path", "dashboard.tpl")
+	for _, s := range settings {
+		v.SetDefault(s.key, s.def)
+	}
 
-	// Bind environment variables
-	// Note: GITHUB_TOKEN is not bound here to prevent accidental exposure via other means.
-	// It's read directly.
+	if err := readConfigFile(v, opts); err != nil {
+		return nil, err
+	}
+
+	if opts.Flags != nil {
+		for _, s := range settings {
+			if flag := opts.Flags.Lookup(s.flagName()); flag != nil {
+				if err := v.BindPFlag(s.key, flag); err != nil {
+					return nil, fmt.Errorf("failed to bind flag --%s: %w", flag.Name, err)
+				}
+			}
+		}
+	}
 
 	var cfg Config
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
-	
-	// Read the GitHub token directly from the environment variable.
-	// This is a more secure practice for sensitive credentials.
-	cfg.GitHubToken = v.GetString("github_token")
+	cfg.configFile = v.ConfigFileUsed()
+	cfg.profile = opts.Profile
+	cfg.effective = resolveSettings(v, opts.Flags)
 
-	// Validate required configuration
-	if cfg.GitHubToken == "" {
-		return nil, fmt.Errorf("required configuration not set: GITHUB_TOKEN")
+	// Credentials are validated per command with ValidateGitHubAuth,
+	// since commands that never touch the network don't need them.
+	return &cfg, nil
+}
+
+// readConfigFile reads the config file, if any, and applies the selected profile on top of it.
+func readConfigFile(v *viper.Viper, opts Options) error {
+	if opts.ConfigFile != "" {
+		v.SetConfigFile(opts.ConfigFile)
+	} else {
+		v.SetConfigName("trendboard")
+		v.AddConfigPath(".")
+		if dir, err := os.UserConfigDir(); err == nil {
+			v.AddConfigPath(filepath.Join(dir, "trendboard"))
+		}
 	}
 
-	return &cfg, nil
+	if err := v.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if opts.ConfigFile == "" && errors.As(err, &notFound) {
+			if opts.Profile != "" {
+				return fmt.Errorf("profile '%s' selected but no config file found", opts.Profile)
+			}
+			return nil
+		}
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if opts.Profile == "" {
+		return nil
+	}
+	profile := v.Sub("profiles." + opts.Profile)
+	if profile == nil {
+		return fmt.Errorf("profile '%s' not found in config file '%s'", opts.Profile, v.ConfigFileUsed())
+	}
+	if err := v.MergeConfigMap(profile.AllSettings()); err != nil {
+		return fmt.Errorf("failed to apply profile '%s': %w", opts.Profile, err)
+	}
+	return nil
+}
+
+// ConfigFileUsed returns the path of the config file that was read, or "" if none was.
+func (c *Config) ConfigFileUsed() string {
+	return c.configFile
+}
+
+// ProfileUsed returns the name of the config file profile that was applied, or "" if none was.
+func (c *Config) ProfileUsed() string {
+	return c.profile
+}
+
+// Settings returns every setting with its effective value and source, in a stable order.
+// Secrets are redacted. It is empty for configurations that weren't created by Load.
+func (c *Config) Settings() []Setting {
+	return c.effective
+}
+
+// Validate checks that the settings with a fixed set of values hold one of them
+// and that the GitHub API settings are consistent.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(key, value string, allowed ...string) {
+		if !slices.Contains(allowed, value) {
+			errs = append(errs, fmt.Errorf("unknown %s '%s' (expected one of: %s)", key, value, strings.Join(allowed, ", ")))
+		}
+	}
+	check("log_level", strings.ToLower(c.LogLevel), "debug", "info", "warn", "error")
+	check("dashboard_format", c.DashboardFormat, "md", "markdown", "html")
+	check("fetcher", c.Fetcher, "rest", "graphql")
+	check("storage_backend", c.StorageBackend, "file", "sqlite")
+	check("github_token_strategy", c.GitHubTokenStrategy, "round-robin", "most-remaining")
+	check("archive_granularity", c.ArchiveGranularity, "daily", "weekly")
+	// Weekly trends compare with the snapshot of a week ago, which must not be thinned out.
+	if c.DailyRetentionDays < 0 || c.ArchiveGranularity == "weekly" && c.DailyRetentionDays < 7 {
+		errs = append(errs, fmt.Errorf("invalid daily_retention_days %d (must be at least 7 with weekly archive_granularity, and not negative)", c.DailyRetentionDays))
+	}
+
+	if c.GitHubBaseURL != "" {
+		if u, err := url.Parse(c.GitHubBaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid github_base_url '%s'", c.GitHubBaseURL))
+		}
+	}
+	if err := c.ValidateGitHubAuth(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// HasCredentials reports whether any GitHub API credentials are configured.
+func (c *Config) HasCredentials() bool {
+	return c.GitHubToken != "" || len(c.GitHubTokens) > 0 || c.GitHubAppID != 0
+}
+
+// ValidateGitHubAuth validates the GitHub API credentials for commands that fetch from GitHub.
+// Having no credentials at all is valid and results in unauthenticated requests.
+func (c *Config) ValidateGitHubAuth() error {
+	if c.GitHubAppID != 0 && (c.GitHubAppInstallationID == 0 || c.GitHubAppPrivateKeyPath == "") {
+		return fmt.Errorf("required configuration not set: GITHUB_APP_INSTALLATION_ID and GITHUB_APP_PRIVATE_KEY_PATH are required with GITHUB_APP_ID")
+	}
+	// A GitHub App authenticates with its own installation tokens, which would leave the pool unused.
+	if c.GitHubAppID != 0 && len(c.GitHubTokens) > 0 {
+		return fmt.Errorf("conflicting configuration: GITHUB_TOKENS cannot be used with GITHUB_APP_ID")
+	}
+	return nil
+}
+
+// GitHubWebURL returns the root URL of the GitHub web interface, with a trailing slash,
+// derived from the configured API base URL.
+func (c *Config) GitHubWebURL() string {
+	if c.GitHubBaseURL == "" {
+		return "https://github.com/"
+	}
+	u, err := url.Parse(c.GitHubBaseURL)
+	if err != nil || u.Host == "" {
+		return "https://github.com/"
+	}
+	// GitHub Enterprise Server serves the web interface at the root of the API host.
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}).String()
 }
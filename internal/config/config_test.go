@@ -2,8 +2,11 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -56,15 +59,221 @@ func TestLoad_DefaultValues(t *testing.T) {
 	assert.Equal(t, "dashboard.md", cfg.DashboardFilePath)
 	assert.Equal(t, "md", cfg.DashboardFormat)
 	assert.Equal(t, "dashboard.tpl", cfg.DashboardTemplatePath)
+	assert.Equal(t, "rest", cfg.Fetcher)
+	assert.Equal(t, 100, cfg.RateLimitThreshold)
+	assert.False(t, cfg.RateLimitWait)
+	assert.Equal(t, 15*time.Minute, cfg.RateLimitMaxWait)
 }
 
-func TestLoad_MissingGitHubToken_Error(t *testing.T) {
-	
-	// Ensure the required GITHUB_TOKEN is not set
+func TestLoad_MissingGitHubToken(t *testing.T) {
+
+	// Commands that never touch the network must work without a token
+	os.Unsetenv("GITHUB_TOKEN")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NotNil(t, cfg)
+	assert.False(t, cfg.HasCredentials())
+	assert.NoError(t, cfg.ValidateGitHubAuth(), "unauthenticated access is valid")
+}
+
+func TestLoad_GitHubApp(t *testing.T) {
+	os.Unsetenv("GITHUB_TOKEN")
+	t.Setenv("GITHUB_APP_ID", "1234")
+	t.Setenv("GITHUB_APP_INSTALLATION_ID", "5678")
+	t.Setenv("GITHUB_APP_PRIVATE_KEY_PATH", "app.pem")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NoError(t, cfg.ValidateGitHubAuth())
+	assert.True(t, cfg.HasCredentials())
+	assert.Equal(t, int64(1234), cfg.GitHubAppID)
+	assert.Equal(t, int64(5678), cfg.GitHubAppInstallationID)
+	assert.Equal(t, "app.pem", cfg.GitHubAppPrivateKeyPath)
+}
+
+func TestConfig_ValidateGitHubAuth_IncompleteGitHubApp(t *testing.T) {
+	os.Unsetenv("GITHUB_TOKEN")
+	t.Setenv("GITHUB_APP_ID", "1234")
+	t.Setenv("GITHUB_APP_INSTALLATION_ID", "")
+	t.Setenv("GITHUB_APP_PRIVATE_KEY_PATH", "app.pem")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	err = cfg.ValidateGitHubAuth()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GITHUB_APP_INSTALLATION_ID")
+}
+
+func TestConfig_ValidateGitHubAuth_GitHubAppWithTokenPool(t *testing.T) {
 	os.Unsetenv("GITHUB_TOKEN")
+	t.Setenv("GITHUB_APP_ID", "1234")
+	t.Setenv("GITHUB_APP_INSTALLATION_ID", "5678")
+	t.Setenv("GITHUB_APP_PRIVATE_KEY_PATH", "app.pem")
+	t.Setenv("GITHUB_TOKENS", "token_a,token_b")
 
 	cfg, err := Load()
+	require.NoError(t, err)
+	err = cfg.ValidateGitHubAuth()
 	require.Error(t, err)
-	assert.Nil(t, cfg)
-	assert.Contains(t, err.Error(), "required configuration not set: GITHUB_TOKEN")
+	assert.Contains(t, err.Error(), "GITHUB_TOKENS")
+}
+
+func TestLoad_GitHubTokens(t *testing.T) {
+	os.Unsetenv("GITHUB_TOKEN")
+	t.Setenv("GITHUB_TOKENS", "token_a,token_b")
+	t.Setenv("GITHUB_TOKEN_STRATEGY", "most-remaining")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"token_a", "token_b"}, cfg.GitHubTokens)
+	assert.Equal(t, "most-remaining", cfg.GitHubTokenStrategy)
+}
+
+func TestConfig_GitHubWebURL(t *testing.T) {
+	testCases := []struct {
+		name     string
+		baseURL  string
+		expected string
+	}{
+		{name: "Public GitHub", baseURL: "", expected: "https://github.com/"},
+		{name: "Enterprise Server", baseURL: "https://ghes.example.com/api/v3/", expected: "https://ghes.example.com/"},
+		{name: "Enterprise Server with port", baseURL: "http://ghes.internal:8080", expected: "http://ghes.internal:8080/"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := &Config{GitHubBaseURL: tc.baseURL}
+			assert.Equal(t, tc.expected, cfg.GitHubWebURL())
+		})
+	}
+}
+
+func TestLoadWithOptions_ConfigFileAndProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trendboard.yaml")
+	content := `
+log_level: warn
+dashboard_format: md
+data_dir_path: file_data
+profiles:
+  internal-ghes:
+    dashboard_format: html
+    github_base_url: https://ghes.example.com/api/v3/
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("DASHBOARD_FORMAT", "")
+	t.Setenv("REPOS_FILE_PATH", "")
+	t.Setenv("DATA_DIR_PATH", "env_data")
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	BindFlags(flags)
+	require.NoError(t, flags.Parse([]string{"--log-level", "debug"}))
+
+	cfg, err := LoadWithOptions(Options{ConfigFile: path, Profile: "internal-ghes", Flags: flags})
+	require.NoError(t, err)
+
+	assert.Equal(t, "debug", cfg.LogLevel, "flags override everything")
+	assert.Equal(t, "env_data", cfg.DataDirPath, "environment overrides the config file")
+	assert.Equal(t, "html", cfg.DashboardFormat, "the profile overrides the top-level settings")
+	assert.Equal(t, "https://ghes.example.com/api/v3/", cfg.GitHubBaseURL)
+	assert.Equal(t, "repos.json", cfg.ReposFilePath, "unset settings keep their defaults")
+}
+
+func TestLoadWithOptions_DiscoversConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "trendboard.toml"), []byte(`dashboard_file_path = "board.md"`), 0644))
+	t.Chdir(dir)
+	t.Setenv("DASHBOARD_FILE_PATH", "")
+
+	cfg, err := LoadWithOptions(Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "board.md", cfg.DashboardFilePath)
+}
+
+func TestLoadWithOptions_Errors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trendboard.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("log_level: info\n"), 0644))
+
+	_, err := LoadWithOptions(Options{ConfigFile: path, Profile: "missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "profile 'missing' not found")
+
+	_, err = LoadWithOptions(Options{ConfigFile: filepath.Join(t.TempDir(), "nope.yaml")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to read config file")
+}
+
+func TestConfig_Settings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trendboard.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("dashboard_format: html\n"), 0644))
+
+	t.Setenv("GITHUB_TOKEN", "test_token_789")
+	t.Setenv("GITHUB_TOKENS", "")
+	t.Setenv("DASHBOARD_FORMAT", "")
+	t.Setenv("DATA_DIR_PATH", "env_data")
+	t.Setenv("LOG_LEVEL", "")
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	BindFlags(flags)
+	require.NoError(t, flags.Parse([]string{"--log-level", "debug"}))
+
+	cfg, err := LoadWithOptions(Options{ConfigFile: path, Flags: flags})
+	require.NoError(t, err)
+	assert.Equal(t, path, cfg.ConfigFileUsed())
+
+	settings := make(map[string]Setting)
+	for _, s := range cfg.Settings() {
+		settings[s.Key] = s
+	}
+	assert.Equal(t, Setting{Key: "github_token", Value: "<redacted>", Source: SourceEnv}, settings["github_token"])
+	assert.Equal(t, Setting{Key: "github_tokens", Value: "", Source: SourceDefault}, settings["github_tokens"])
+	assert.Equal(t, Setting{Key: "log_level", Value: "debug", Source: SourceFlag}, settings["log_level"])
+	assert.Equal(t, Setting{Key: "data_dir_path", Value: "env_data", Source: SourceEnv}, settings["data_dir_path"])
+	assert.Equal(t, Setting{Key: "dashboard_format", Value: "html", Source: SourceFile}, settings["dashboard_format"])
+	assert.Equal(t, Setting{Key: "fetcher", Value: "rest", Source: SourceDefault}, settings["fetcher"])
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := Config{
+		LogLevel:            "info",
+		DashboardFormat:     "md",
+		Fetcher:             "rest",
+		GitHubTokenStrategy: "round-robin",
+		StorageBackend:      "file",
+		ArchiveGranularity:  "daily",
+	}
+	require.NoError(t, valid.Validate())
+
+	invalid := valid
+	invalid.DashboardFormat = "pdf"
+	invalid.Fetcher = "soap"
+	invalid.StorageBackend = "postgres"
+	invalid.GitHubBaseURL = "ghes.example.com"
+	err := invalid.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown dashboard_format 'pdf'")
+	assert.Contains(t, err.Error(), "unknown fetcher 'soap'")
+	assert.Contains(t, err.Error(), "unknown storage_backend 'postgres'")
+	assert.Contains(t, err.Error(), "invalid github_base_url")
+
+	weekly := valid
+	weekly.ArchiveGranularity = "weekly"
+	weekly.DailyRetentionDays = 3
+	err = weekly.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid daily_retention_days 3")
+}
+
+func TestLoadWithOptions_CategoryFlag(t *testing.T) {
+	t.Setenv("DASHBOARD_CATEGORIES", "")
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	BindFlags(flags)
+	require.NoError(t, flags.Parse([]string{"--category", "web,orm"}))
+
+	cfg, err := LoadWithOptions(Options{Flags: flags})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web", "orm"}, cfg.DashboardCategories)
 }
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// setting describes a single configuration key along with its default value.
+type setting struct {
+	// key is the name of the setting in config files; its environment variable is the upper-cased key.
+	key string
+	// def is the default value, which also determines the type of the setting's flag.
+	def any
+	// usage is the help text of the setting's flag.
+	usage string
+	// flag overrides the flag name derived from key, for settings that read better with a short flag.
+	flag string
+	// secret settings are never exposed as flags, so that they can't leak through shell history or process lists.
+	secret bool
+}
+
+// settings lists every configuration key. It drives defaults and command-line flags.
+var settings = []setting{
+	{key: "github_token", def: "", usage: "token for authenticating with the GitHub API", secret: true},
+	{key: "github_tokens", def: []string{}, usage: "additional tokens rotated together with the GitHub token", secret: true},
+	{key: "github_token_strategy", def: "round-robin", usage: "how tokens are picked from the pool (round-robin or most-remaining)"},
+	{key: "github_base_url", def: "", usage: "REST API base URL of a GitHub Enterprise Server instance"},
+	{key: "github_upload_url", def: "", usage: "upload API URL of a GitHub Enterprise Server instance"},
+	{key: "github_app_id", def: int64(0), usage: "ID of the GitHub App to authenticate as"},
+	{key: "github_app_installation_id", def: int64(0), usage: "ID of the GitHub App installation"},
+	{key: "github_app_private_key_path", def: "", usage: "path to the GitHub App private key (PEM)"},
+	{key: "log_level", def: "info", usage: "logging level (debug, info, warn, error)"},
+	{key: "repos_file_path", def: "repos.json", usage: "path to the list of repositories to track"},
+	{key: "data_dir_path", def: "data", usage: "directory where daily trend data is stored"},
+	{key: "storage_backend", def: "file", usage: "where trend data is stored (file or sqlite)"},
+	{key: "sqlite_path", def: "data/trendboard.db", usage: "path to the SQLite database used by the sqlite storage backend"},
+	{key: "dashboard_file_path", def: "dashboard.md", usage: "path of the generated dashboard"},
+	{key: "dashboard_format", def: "md", usage: "format of the generated dashboard (md or html)"},
+	{key: "dashboard_template_path", def: "dashboard.tpl", usage: "path to the HTML dashboard template"},
+	{key: "fetcher", def: "rest", usage: "GitHub API used to fetch star counts (rest or graphql)"},
+	{key: "graphql_batch_size", def: 50, usage: "number of repositories fetched per GraphQL query"},
+	{key: "rate_limit_threshold", def: 100, usage: "remaining quota below which requests are spread until the reset"},
+	{key: "rate_limit_wait", def: false, usage: "wait for the rate limit to reset once the quota is exhausted"},
+	{key: "rate_limit_max_wait", def: 15 * time.Minute, usage: "longest time to wait for a rate limit reset"},
+	{key: "retry_max_attempts", def: 3, usage: "total attempts per repository on transient errors"},
+	{key: "retry_initial_backoff", def: time.Second, usage: "delay before the first retry"},
+	{key: "retry_max_backoff", def: 30 * time.Second, usage: "maximum delay between retries"},
+	{key: "retry_jitter", def: 0.2, usage: "fraction of each retry delay that is randomised"},
+	{key: "carry_forward", def: true, usage: "carry forward the last known star count for failed fetches"},
+	{key: "carry_forward_max_days", def: 7, usage: "how many days back to look for the last known star count"},
+	{key: "intraday_samples", def: false, usage: "keep every star count fetched during a day, not just the latest"},
+	{key: "daily_retention_days", def: 90, usage: "days for which snapshots are kept as daily files before compact archives them"},
+	{key: "archive_granularity", def: "daily", usage: "snapshots kept in archives (daily, or weekly to keep the last one of each week)"},
+	{key: "http_cache_dir", def: "", usage: "directory for caching GitHub API responses (disabled when empty)"},
+	{key: "index_cache_dir", def: "", usage: "directory for caching snapshot summaries of the file backend (user cache directory when empty)"},
+	{key: "discover_queries", def: []string{"language:Go stars:>500 created:>{today-90d} archived:false"}, usage: "GitHub search queries used by discover; {today-Nd} expands to the date N days ago"},
+	{key: "discover_limit", def: 20, usage: "maximum number of search results per discover query"},
+	{key: "dashboard_categories", def: []string{}, usage: "categories (repository tags) to render leaderboards for; all when empty", flag: "category"},
+}
+
+// Source identifies where the effective value of a setting came from.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// redacted replaces the value of secret settings in Settings.
+const redacted = "<redacted>"
+
+// Setting is the effective value of a configuration key and its source.
+type Setting struct {
+	// Key is the name of the setting in config files.
+	Key string
+	// Value is the effective value, formatted for display. Secrets are redacted.
+	Value string
+	// Source is where the effective value came from.
+	Source Source
+}
+
+// resolveSettings determines the effective value and source of every setting.
+func resolveSettings(v *viper.Viper, flags *pflag.FlagSet) []Setting {
+	resolved := make([]Setting, 0, len(settings))
+	for _, s := range settings {
+		source := SourceDefault
+		switch {
+		case flags != nil && flags.Lookup(s.flagName()) != nil && flags.Changed(s.flagName()):
+			source = SourceFlag
+		case os.Getenv(strings.ToUpper(s.key)) != "":
+			source = SourceEnv
+		case v.InConfig(s.key):
+			source = SourceFile
+		}
+
+		value := v.GetString(s.key)
+		if _, ok := s.def.([]string); ok {
+			value = strings.Join(v.GetStringSlice(s.key), ",")
+		}
+		if s.secret && value != "" {
+			value = redacted
+		}
+		resolved = append(resolved, Setting{Key: s.key, Value: value, Source: source})
+	}
+	return resolved
+}
+
+// FlagName returns the default command-line flag name of a configuration key.
+func FlagName(key string) string {
+	return strings.ReplaceAll(key, "_", "-")
+}
+
+// flagName returns the command-line flag name of the setting.
+func (s setting) flagName() string {
+	if s.flag != "" {
+		return s.flag
+	}
+	return FlagName(s.key)
+}
+
+// BindFlags registers a flag for every non-secret setting on flags.
+// Pass the same flag set to Load through Options.Flags so that explicitly set flags take precedence.
+func BindFlags(flags *pflag.FlagSet) {
+	for _, s := range settings {
+		if s.secret {
+			continue
+		}
+		name := s.flagName()
+		usage := fmt.Sprintf("%s (env %s)", s.usage, strings.ToUpper(s.key))
+		switch def := s.def.(type) {
+		case string:
+			flags.String(name, def, usage)
+		case int:
+			flags.Int(name, def, usage)
+		case int64:
+			flags.Int64(name, def, usage)
+		case bool:
+			flags.Bool(name, def, usage)
+		case float64:
+			flags.Float64(name, def, usage)
+		case time.Duration:
+			flags.Duration(name, def, usage)
+		case []string:
+			flags.StringSlice(name, def, usage)
+		default:
+			panic(fmt.Sprintf("config: unsupported type %T for setting %s", def, s.key))
+		}
+	}
+}
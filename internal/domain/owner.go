@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OwnerKind is the kind of GitHub account that owns repositories.
+type OwnerKind string
+
+const (
+	OwnerOrg  OwnerKind = "org"
+	OwnerUser OwnerKind = "user"
+)
+
+// Owner is a GitHub organization or user whose repositories are tracked as a whole.
+// In repos.json it is written as "org:<name>" or "user:<name>".
+type Owner struct {
+	Kind OwnerKind
+	Name string
+}
+
+// String returns the owner in its "org:<name>" or "user:<name>" form.
+func (o Owner) String() string {
+	return string(o.Kind) + ":" + o.Name
+}
+
+// ParseOwner parses an "org:<name>" or "user:<name>" entry.
+// It reports false for entries that don't name an owner, such as "owner/name".
+func ParseOwner(entry string) (Owner, bool) {
+	kind, name, ok := strings.Cut(strings.TrimSpace(entry), ":")
+	if !ok {
+		return Owner{}, false
+	}
+	switch OwnerKind(strings.ToLower(kind)) {
+	case OwnerOrg, OwnerUser:
+	default:
+		return Owner{}, false
+	}
+	return Owner{Kind: OwnerKind(strings.ToLower(kind)), Name: strings.ToLower(strings.TrimSpace(name))}, true
+}
+
+// NormalizeTargetName normalizes an entry of the repository list: either an owner
+// ("org:<name>" or "user:<name>") or a repository, as accepted by NormalizeRepoName.
+func NormalizeTargetName(input string) (string, error) {
+	owner, ok := ParseOwner(input)
+	if !ok {
+		return NormalizeRepoName(input)
+	}
+	if owner.Name == "" || strings.Contains(owner.Name, "/") {
+		return "", fmt.Errorf("invalid owner name format: %s", input)
+	}
+	return owner.String(), nil
+}
+
+// OwnedRepo describes a repository listed for an owner, with the attributes owner entries filter on.
+type OwnedRepo struct {
+	// FullName is the full name of the repository in "owner/name" format.
+	FullName string
+	// Stars is the current number of stars.
+	Stars int
+	// Language is the primary language of the repository, if GitHub detected one.
+	Language string
+	// Fork reports whether the repository is a fork.
+	Fork bool
+	// Archived reports whether the repository is archived.
+	Archived bool
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOwner(t *testing.T) {
+	t.Parallel()
+
+	owner, ok := ParseOwner("org:Uber-Go")
+	require.True(t, ok)
+	assert.Equal(t, Owner{Kind: OwnerOrg, Name: "uber-go"}, owner)
+	assert.Equal(t, "org:uber-go", owner.String())
+
+	owner, ok = ParseOwner(" user:spf13 ")
+	require.True(t, ok)
+	assert.Equal(t, Owner{Kind: OwnerUser, Name: "spf13"}, owner)
+
+	_, ok = ParseOwner("spf13/cobra")
+	assert.False(t, ok)
+	_, ok = ParseOwner("team:spf13")
+	assert.False(t, ok)
+}
+
+func TestNormalizeTargetName(t *testing.T) {
+	t.Parallel()
+
+	name, err := NormalizeTargetName("ORG:uber-go")
+	require.NoError(t, err)
+	assert.Equal(t, "org:uber-go", name)
+
+	name, err = NormalizeTargetName("github.com/Spf13/Cobra")
+	require.NoError(t, err)
+	assert.Equal(t, "spf13/cobra", name)
+
+	_, err = NormalizeTargetName("org:")
+	require.Error(t, err)
+	_, err = NormalizeTargetName("user:spf13/cobra")
+	require.Error(t, err)
+}
+
+func TestTargetRepo_Includes(t *testing.T) {
+	t.Parallel()
+
+	target := TargetRepo{Name: "org:uber-go", Language: "go", MinStars: 100}
+	testCases := []struct {
+		name string
+		repo OwnedRepo
+		want bool
+	}{
+		{name: "Matching", repo: OwnedRepo{Language: "Go", Stars: 100}, want: true},
+		{name: "Too few stars", repo: OwnedRepo{Language: "Go", Stars: 99}, want: false},
+		{name: "Other language", repo: OwnedRepo{Language: "Rust", Stars: 500}, want: false},
+		{name: "Fork", repo: OwnedRepo{Language: "Go", Stars: 500, Fork: true}, want: false},
+		{name: "Archived", repo: OwnedRepo{Language: "Go", Stars: 500, Archived: true}, want: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, target.Includes(tc.repo))
+		})
+	}
+
+	inclusive := TargetRepo{Name: "user:spf13", IncludeForks: true, IncludeArchived: true}
+	assert.True(t, inclusive.Includes(OwnedRepo{Fork: true, Archived: true}))
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Rename records that a tracked repository was renamed or transferred on GitHub.
+type Rename struct {
+	// From is the name the repository was tracked under.
+	From string `json:"from"`
+	// To is the canonical name GitHub redirected to.
+	To string `json:"to"`
+	// ID is GitHub's numeric identifier of the repository, if the fetcher reported it.
+	ID int64 `json:"id,omitempty"`
+	// DetectedAt is when the rename was first detected.
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// RecordRename adds rename to renames, replacing any earlier record from the same name.
+// A record whose From is the new name is dropped, so that renaming a repository back
+// doesn't leave a cycle. It reports whether renames changed.
+func RecordRename(renames []Rename, rename Rename) ([]Rename, bool) {
+	for _, r := range renames {
+		if strings.EqualFold(r.From, rename.From) && strings.EqualFold(r.To, rename.To) {
+			return renames, false
+		}
+	}
+
+	kept := make([]Rename, 0, len(renames)+1)
+	for _, r := range renames {
+		if !strings.EqualFold(r.From, rename.From) && !strings.EqualFold(r.From, rename.To) {
+			kept = append(kept, r)
+		}
+	}
+	return append(kept, rename), true
+}
+
+// RenameMap resolves repository names to their current names by following recorded renames.
+type RenameMap map[string]string
+
+// NewRenameMap builds a RenameMap from the recorded renames.
+func NewRenameMap(renames []Rename) RenameMap {
+	m := make(RenameMap, len(renames))
+	for _, r := range renames {
+		m[strings.ToLower(r.From)] = r.To
+	}
+	return m
+}
+
+// Resolve returns the current name of the repository tracked as name, following
+// chains of renames, or name itself if it was never renamed.
+func (m RenameMap) Resolve(name string) string {
+	// The number of hops is bounded so that inconsistent records can't loop forever.
+	for range len(m) {
+		to, ok := m[strings.ToLower(name)]
+		if !ok {
+			break
+		}
+		name = to
+	}
+	return name
+}
+
+// ResolveKey returns the lower-cased current name of the repository tracked as name,
+// for matching repositories across snapshots taken before and after a rename.
+func (m RenameMap) ResolveKey(name string) string {
+	return strings.ToLower(m.Resolve(name))
+}
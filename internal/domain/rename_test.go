@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFetchedRepository(t *testing.T) {
+	t.Parallel()
+
+	repo, err := NewFetchedRepository("owner/repo", "Owner/Repo", 42, 100)
+	require.NoError(t, err)
+	assert.Equal(t, &Repository{ID: 42, FullName: "owner/repo", Stars: 100, Status: StatusOK}, repo)
+
+	repo, err = NewFetchedRepository("owner/repo", "", 0, 100)
+	require.NoError(t, err)
+	assert.Equal(t, &Repository{FullName: "owner/repo", Stars: 100, Status: StatusOK}, repo)
+
+	repo, err = NewFetchedRepository("old-owner/old-name", "new-owner/new-name", 42, 100)
+	require.NoError(t, err)
+	assert.Equal(t, &Repository{
+		ID:          42,
+		FullName:    "new-owner/new-name",
+		Stars:       100,
+		Status:      StatusRenamed,
+		RenamedFrom: "old-owner/old-name",
+	}, repo)
+	assert.True(t, repo.HasStars())
+}
+
+func TestRecordRename(t *testing.T) {
+	t.Parallel()
+
+	first := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	renames, changed := RecordRename(nil, Rename{From: "a/old", To: "a/new", DetectedAt: first})
+	require.True(t, changed)
+
+	// Detecting the same rename again keeps the first record.
+	renames, changed = RecordRename(renames, Rename{From: "A/Old", To: "a/new", DetectedAt: first.AddDate(0, 0, 1)})
+	assert.False(t, changed)
+	assert.Equal(t, []Rename{{From: "a/old", To: "a/new", DetectedAt: first}}, renames)
+
+	// Renaming back drops the record pointing the other way.
+	renames, changed = RecordRename(renames, Rename{From: "a/new", To: "a/old", DetectedAt: first})
+	assert.True(t, changed)
+	assert.Equal(t, []Rename{{From: "a/new", To: "a/old", DetectedAt: first}}, renames)
+}
+
+func TestRenameMap_Resolve(t *testing.T) {
+	t.Parallel()
+
+	m := NewRenameMap([]Rename{
+		{From: "a/one", To: "a/two"},
+		{From: "a/two", To: "b/Three"},
+		{From: "x/loop", To: "y/loop"},
+		{From: "y/loop", To: "x/loop"},
+	})
+
+	assert.Equal(t, "b/Three", m.Resolve("A/One"))
+	assert.Equal(t, "b/three", m.ResolveKey("a/two"))
+	assert.Equal(t, "c/other", m.Resolve("c/other"))
+	assert.NotPanics(t, func() { m.Resolve("x/loop") })
+}
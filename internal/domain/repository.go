@@ -3,14 +3,49 @@ package domain
 import (
 	"fmt"
 	"strings"
+	"time"
+)
+
+// FetchStatus describes the outcome of fetching a repository for a snapshot.
+type FetchStatus string
+
+const (
+	StatusOK       FetchStatus = "ok"
+	StatusFailed   FetchStatus = "failed"
+	StatusNotFound FetchStatus = "not_found"
+	StatusRenamed  FetchStatus = "renamed"
 )
 
 // Repository represents a single GitHub repository being tracked.
 type Repository struct {
+	// ID is GitHub's numeric identifier of the repository, which survives renames and transfers.
+	// It is zero when the fetcher didn't report it.
+	ID int64 `json:",omitempty"`
 	// FullName is the full name of the repository in "owner/name" format.
 	FullName string
 	// Stars is the current number of stars.
 	Stars int
+	// Status is the outcome of fetching the repository.
+	// Snapshots written before statuses were recorded leave it empty, which means ok.
+	Status FetchStatus `json:",omitempty"`
+	// Stale reports whether Stars was carried forward from an earlier snapshot
+	// because the repository could not be fetched.
+	Stale bool `json:",omitempty"`
+	// RenamedFrom is the name the repository was requested under when GitHub
+	// redirected the request because the repository was renamed or transferred.
+	RenamedFrom string `json:",omitempty"`
+	// FetchedAt is when Stars was fetched from GitHub; for a stale repository, when the carried
+	// forward value was. It is zero in snapshots written before fetch times were recorded.
+	FetchedAt time.Time `json:",omitzero"`
+	// Samples are the star counts fetched during the day, in chronological order, when
+	// intra-day samples are kept. Stars is the latest of them.
+	Samples []Sample `json:",omitempty"`
+}
+
+// Sample is a star count fetched at a given time.
+type Sample struct {
+	FetchedAt time.Time
+	Stars     int
 }
 
 // NewRepository creates a new Repository object.
@@ -27,5 +62,124 @@ func NewRepository(fullName string, stars int) (*Repository, error) {
 	return &Repository{
 		FullName: fullName,
 		Stars:    stars,
+		Status:   StatusOK,
 	}, nil
 }
+
+// NewFetchedRepository creates a Repository from a successful fetch of requestedName that GitHub
+// answered with the repository canonicalName. When the names differ beyond case, the repository
+// was renamed or transferred: it then carries its canonical name and StatusRenamed.
+// An empty canonicalName means the fetcher didn't report it.
+func NewFetchedRepository(requestedName, canonicalName string, id int64, stars int) (*Repository, error) {
+	if canonicalName == "" || strings.EqualFold(canonicalName, requestedName) {
+		repo, err := NewRepository(requestedName, stars)
+		if err != nil {
+			return nil, err
+		}
+		repo.ID = id
+		return repo, nil
+	}
+
+	repo, err := NewRepository(canonicalName, stars)
+	if err != nil {
+		return nil, err
+	}
+	repo.ID = id
+	repo.Status = StatusRenamed
+	repo.RenamedFrom = requestedName
+	return repo, nil
+}
+
+// Key returns the identity of the repository for matching it across snapshots.
+// GitHub treats repository names case-insensitively, so the key is lower-cased.
+func (r *Repository) Key() string {
+	return strings.ToLower(r.FullName)
+}
+
+// NormalizeRepoName turns user input such as "Owner/Repo", "github.com/owner/repo"
+// or "https://github.com/owner/repo.git" into a validated, lower-cased "owner/name".
+func NormalizeRepoName(input string) (string, error) {
+	name := strings.TrimSpace(input)
+	for _, prefix := range []string{"https://", "http://"} {
+		name = strings.TrimPrefix(name, prefix)
+	}
+	name = strings.TrimPrefix(name, "www.")
+	name = strings.TrimPrefix(name, "github.com/")
+	name = strings.TrimSuffix(name, "/")
+	name = strings.TrimSuffix(name, ".git")
+	name = strings.ToLower(name)
+
+	if _, err := NewRepository(name, 0); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// NewFailedRepository creates a Repository recording that fetching fullName failed with the given status.
+// It carries no star count until one is carried forward with CarryForward.
+func NewFailedRepository(fullName string, status FetchStatus) *Repository {
+	return &Repository{
+		FullName: fullName,
+		Status:   status,
+	}
+}
+
+// CarryForward sets the star count to the last known value and marks it as stale.
+func (r *Repository) CarryForward(lastKnown *Repository) {
+	r.Stars = lastKnown.Stars
+	r.FetchedAt = lastKnown.FetchedAt
+	r.Stale = true
+}
+
+// HasStars reports whether the star count holds a usable value,
+// either freshly fetched or carried forward from an earlier snapshot.
+func (r *Repository) HasStars() bool {
+	switch r.Status {
+	case "", StatusOK, StatusRenamed:
+		return true
+	default:
+		return r.Stale
+	}
+}
+
+// IsFresh reports whether the star count was fetched for this snapshot,
+// as opposed to carried forward or missing because the fetch failed.
+func (r *Repository) IsFresh() bool {
+	return r.HasStars() && !r.Stale
+}
+
+// RepositoryIndex finds the entries of a snapshot that correspond to repositories of another
+// snapshot. Repositories are matched by GitHub ID when both sides know it, and by name otherwise,
+// following recorded renames, so that snapshots written before IDs were recorded still match.
+type RepositoryIndex struct {
+	byID    map[int64]*Repository
+	byName  map[string]*Repository
+	renames RenameMap
+}
+
+// NewRepositoryIndex indexes the repositories of a snapshot.
+func NewRepositoryIndex(repos []*Repository, renames RenameMap) *RepositoryIndex {
+	index := &RepositoryIndex{
+		byID:    make(map[int64]*Repository, len(repos)),
+		byName:  make(map[string]*Repository, len(repos)),
+		renames: renames,
+	}
+	for _, repo := range repos {
+		if repo.ID != 0 {
+			index.byID[repo.ID] = repo
+		}
+		index.byName[renames.ResolveKey(repo.FullName)] = repo
+	}
+	return index
+}
+
+// Find returns the indexed entry for repo.
+func (i *RepositoryIndex) Find(repo *Repository) (*Repository, bool) {
+	if repo.ID != 0 {
+		if found, ok := i.byID[repo.ID]; ok {
+			return found, true
+		}
+	}
+	found, ok := i.byName[i.renames.ResolveKey(repo.FullName)]
+	return found, ok
+}
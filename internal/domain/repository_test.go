@@ -78,3 +78,86 @@ func TestNewRepository(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_HasStars(t *testing.T) {
+	t.Parallel()
+
+	fetched, err := NewRepository("owner/repo", 10)
+	require.NoError(t, err)
+	assert.Equal(t, StatusOK, fetched.Status)
+	assert.True(t, fetched.HasStars())
+
+	legacy := &Repository{FullName: "owner/repo", Stars: 10}
+	assert.True(t, legacy.HasStars(), "snapshots without a status are treated as ok")
+
+	failed := NewFailedRepository("owner/repo", StatusFailed)
+	assert.False(t, failed.HasStars())
+
+	failed.CarryForward(fetched)
+	assert.True(t, failed.HasStars())
+	assert.True(t, failed.Stale)
+	assert.Equal(t, 10, failed.Stars)
+}
+
+func TestNormalizeRepoName(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "owner/repo", want: "owner/repo"},
+		{input: "  Owner/Repo ", want: "owner/repo"},
+		{input: "github.com/owner/repo", want: "owner/repo"},
+		{input: "https://github.com/Owner/Repo.git", want: "owner/repo"},
+		{input: "https://www.github.com/owner/repo/", want: "owner/repo"},
+		{input: "owner", wantErr: true},
+		{input: "https://github.com/owner/repo/issues", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+			got, err := NormalizeRepoName(tc.input)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRepositoryIndex_Find(t *testing.T) {
+	t.Parallel()
+
+	past := []*Repository{
+		{ID: 1, FullName: "owner/by-id", Stars: 10},
+		{FullName: "Owner/Legacy", Stars: 20},
+		{FullName: "old/name", Stars: 30},
+	}
+	index := NewRepositoryIndex(past, NewRenameMap([]Rename{{From: "old/name", To: "new/name"}}))
+
+	testCases := []struct {
+		name     string
+		repo     *Repository
+		expected *Repository
+	}{
+		{name: "Matched by ID despite a new name", repo: &Repository{ID: 1, FullName: "owner/renamed"}, expected: past[0]},
+		{name: "Matched by name without a past ID", repo: &Repository{ID: 2, FullName: "owner/legacy"}, expected: past[1]},
+		{name: "Matched through a recorded rename", repo: &Repository{FullName: "new/name"}, expected: past[2]},
+		{name: "Not found", repo: &Repository{ID: 3, FullName: "owner/other"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			found, ok := index.Find(tc.repo)
+			assert.Equal(t, tc.expected != nil, ok)
+			assert.Same(t, tc.expected, found)
+		})
+	}
+}
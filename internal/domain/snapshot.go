@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// Snapshot is the state of the tracked repositories on a given date.
+type Snapshot struct {
+	// Date is the day the snapshot was taken, at midnight UTC.
+	Date time.Time
+	// Repositories are the repositories of the snapshot, in the order they were saved.
+	Repositories []*Repository
+}
+
+// SeriesPoint is the state of a single repository on a given date.
+type SeriesPoint struct {
+	// Date is the day of the snapshot the point was taken from, at midnight UTC.
+	Date time.Time
+	// Repository is the repository as saved in that snapshot.
+	Repository *Repository
+}
+
+// MergeSnapshot merges the repositories fetched by an update into the snapshot saved earlier the
+// same day, so that running update several times a day never loses a value an earlier run fetched.
+// For each repository, the freshly fetched value wins over a failed or carried-forward one, and the
+// later fetch wins otherwise. Saved repositories the update didn't fetch are kept as they are, and
+// the intra-day samples of both sides are combined. Repositories are matched by GitHub ID when both
+// sides know it, and by name otherwise.
+func MergeSnapshot(saved, fetched []*Repository) []*Repository {
+	merged := slices.Clone(saved)
+	byID := make(map[int64]int, len(saved))
+	byName := make(map[string]int, len(saved))
+	for i, repo := range saved {
+		if repo.ID != 0 {
+			byID[repo.ID] = i
+		}
+		byName[repo.Key()] = i
+	}
+
+	for _, repo := range fetched {
+		i, ok := byID[repo.ID] // Unknown IDs (zero) are never indexed.
+		if !ok {
+			i, ok = byName[repo.Key()]
+		}
+		if !ok {
+			merged = append(merged, repo)
+			continue
+		}
+		// The merged entry is a copy, so that neither side's repository is modified.
+		entry := *merged[i]
+		if repo.supersedes(merged[i]) {
+			entry = *repo
+		}
+		entry.Samples = mergeSamples(merged[i].Samples, repo.Samples)
+		merged[i] = &entry
+	}
+	return merged
+}
+
+// supersedes reports whether r holds a better value for the snapshot than other,
+// an entry of the same repository.
+func (r *Repository) supersedes(other *Repository) bool {
+	if r.IsFresh() != other.IsFresh() {
+		return r.IsFresh()
+	}
+	return !r.FetchedAt.Before(other.FetchedAt)
+}
+
+// mergeSamples combines two lists of intra-day samples in chronological order, dropping
+// duplicates of the same fetch.
+func mergeSamples(a, b []Sample) []Sample {
+	if len(b) == 0 {
+		return a
+	}
+	samples := append(slices.Clone(a), b...)
+	slices.SortStableFunc(samples, func(x, y Sample) int { return x.FetchedAt.Compare(y.FetchedAt) })
+	return slices.CompactFunc(samples, func(x, y Sample) bool { return x.FetchedAt.Equal(y.FetchedAt) })
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeSnapshot(t *testing.T) {
+	t.Parallel()
+
+	morning := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	evening := morning.Add(10 * time.Hour)
+
+	tests := []struct {
+		name    string
+		saved   []*Repository
+		fetched []*Repository
+		want    []*Repository
+	}{
+		{
+			name:    "nothing saved yet",
+			fetched: []*Repository{{FullName: "owner/repo", Stars: 10, Status: StatusOK, FetchedAt: morning}},
+			want:    []*Repository{{FullName: "owner/repo", Stars: 10, Status: StatusOK, FetchedAt: morning}},
+		},
+		{
+			name:    "later fetch wins",
+			saved:   []*Repository{{FullName: "owner/repo", Stars: 10, Status: StatusOK, FetchedAt: morning}},
+			fetched: []*Repository{{FullName: "Owner/Repo", Stars: 12, Status: StatusOK, FetchedAt: evening}},
+			want:    []*Repository{{FullName: "Owner/Repo", Stars: 12, Status: StatusOK, FetchedAt: evening}},
+		},
+		{
+			name:    "failed fetch keeps the earlier value",
+			saved:   []*Repository{{FullName: "owner/repo", Stars: 10, Status: StatusOK, FetchedAt: morning}},
+			fetched: []*Repository{{FullName: "owner/repo", Stars: 8, Status: StatusFailed, Stale: true}},
+			want:    []*Repository{{FullName: "owner/repo", Stars: 10, Status: StatusOK, FetchedAt: morning}},
+		},
+		{
+			name:    "fetch replaces an earlier failure",
+			saved:   []*Repository{{FullName: "owner/repo", Status: StatusFailed}},
+			fetched: []*Repository{{FullName: "owner/repo", Stars: 12, Status: StatusOK, FetchedAt: evening}},
+			want:    []*Repository{{FullName: "owner/repo", Stars: 12, Status: StatusOK, FetchedAt: evening}},
+		},
+		{
+			name:    "repositories not fetched again are kept",
+			saved:   []*Repository{{FullName: "owner/repo1", Stars: 10, Status: StatusOK}},
+			fetched: []*Repository{{FullName: "owner/repo2", Stars: 20, Status: StatusOK}},
+			want: []*Repository{
+				{FullName: "owner/repo1", Stars: 10, Status: StatusOK},
+				{FullName: "owner/repo2", Stars: 20, Status: StatusOK},
+			},
+		},
+		{
+			name:    "renamed repository matches by ID",
+			saved:   []*Repository{{ID: 42, FullName: "old/name", Stars: 10, Status: StatusOK, FetchedAt: morning}},
+			fetched: []*Repository{{ID: 42, FullName: "new/name", Stars: 12, Status: StatusRenamed, RenamedFrom: "old/name", FetchedAt: evening}},
+			want:    []*Repository{{ID: 42, FullName: "new/name", Stars: 12, Status: StatusRenamed, RenamedFrom: "old/name", FetchedAt: evening}},
+		},
+		{
+			name: "samples are combined",
+			saved: []*Repository{{FullName: "owner/repo", Stars: 10, Status: StatusOK, FetchedAt: morning,
+				Samples: []Sample{{FetchedAt: morning, Stars: 10}}}},
+			fetched: []*Repository{{FullName: "owner/repo", Stars: 12, Status: StatusOK, FetchedAt: evening,
+				Samples: []Sample{{FetchedAt: evening, Stars: 12}}}},
+			want: []*Repository{{FullName: "owner/repo", Stars: 12, Status: StatusOK, FetchedAt: evening,
+				Samples: []Sample{{FetchedAt: morning, Stars: 10}, {FetchedAt: evening, Stars: 12}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, MergeSnapshot(tt.saved, tt.fetched))
+		})
+	}
+}
+
+func TestMergeSnapshot_LeavesInputsUnchanged(t *testing.T) {
+	t.Parallel()
+
+	morning := time.Date(2025, 1, 1, 8, 0, 0, 0, time.UTC)
+	evening := morning.Add(10 * time.Hour)
+	newInputs := func() (saved, fetched []*Repository) {
+		saved = []*Repository{
+			{FullName: "owner/repo1", Stars: 10, Status: StatusOK, FetchedAt: morning, Samples: []Sample{{FetchedAt: morning, Stars: 10}}},
+			{FullName: "owner/repo2", Stars: 20, Status: StatusOK, FetchedAt: morning, Samples: []Sample{{FetchedAt: morning, Stars: 20}}},
+		}
+		fetched = []*Repository{
+			{FullName: "owner/repo1", Stars: 12, Status: StatusOK, FetchedAt: evening, Samples: []Sample{{FetchedAt: evening, Stars: 12}}},
+			{FullName: "owner/repo2", Status: StatusFailed, Samples: []Sample{{FetchedAt: evening, Stars: 0}}},
+		}
+		return saved, fetched
+	}
+
+	saved, fetched := newInputs()
+	merged := MergeSnapshot(saved, fetched)
+	assert.Len(t, merged[0].Samples, 2)
+	assert.Len(t, merged[1].Samples, 2)
+
+	wantSaved, wantFetched := newInputs()
+	assert.Equal(t, wantSaved, saved)
+	assert.Equal(t, wantFetched, fetched)
+}
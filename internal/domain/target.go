@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TargetRepo is an entry of the list of repositories to track.
+// In repos.json it is either a plain "owner/name" string or an object carrying metadata.
+// Entries naming an owner ("org:<name>" or "user:<name>") track all of its public repositories.
+type TargetRepo struct {
+	// Name is the full name of the repository in "owner/name" format,
+	// or an owner in "org:<name>" or "user:<name>" format.
+	Name string `json:"name"`
+	// DisplayName is shown on the dashboard instead of Name when set.
+	DisplayName string `json:"display_name,omitempty"`
+	// Tags are the categories the repository belongs to, such as "web" or "orm".
+	Tags []string `json:"tags,omitempty"`
+	// Notes is free-form text for maintainers of the list; it is not rendered.
+	Notes string `json:"notes,omitempty"`
+	// Disabled excludes the repository from updates without removing it from the list.
+	// A disabled repository entry also excludes the repository from the expansion of owner entries.
+	Disabled bool `json:"disabled,omitempty"`
+
+	// The following filters only apply to owner entries.
+
+	// Language restricts an owner's repositories to those with this primary language.
+	Language string `json:"language,omitempty"`
+	// MinStars restricts an owner's repositories to those with at least this many stars.
+	MinStars int `json:"min_stars,omitempty"`
+	// IncludeForks includes an owner's forked repositories, which are skipped by default.
+	IncludeForks bool `json:"include_forks,omitempty"`
+	// IncludeArchived includes an owner's archived repositories, which are skipped by default.
+	IncludeArchived bool `json:"include_archived,omitempty"`
+}
+
+// Owner returns the owner named by the entry, if it is an owner entry.
+func (t TargetRepo) Owner() (Owner, bool) {
+	return ParseOwner(t.Name)
+}
+
+// Validate checks that the entry names a repository in "owner/name" format or an owner.
+func (t TargetRepo) Validate() error {
+	if _, ok := t.Owner(); ok {
+		_, err := NormalizeTargetName(t.Name)
+		return err
+	}
+	_, err := NewRepository(t.Name, 0)
+	return err
+}
+
+// Includes reports whether a repository of the entry's owner passes the entry's filters.
+func (t TargetRepo) Includes(repo OwnedRepo) bool {
+	switch {
+	case repo.Fork && !t.IncludeForks:
+		return false
+	case repo.Archived && !t.IncludeArchived:
+		return false
+	case t.Language != "" && !strings.EqualFold(repo.Language, t.Language):
+		return false
+	default:
+		return repo.Stars >= t.MinStars
+	}
+}
+
+// ForOwnedRepo returns the entry of a repository tracked through this owner entry.
+// It inherits the owner entry's tags.
+func (t TargetRepo) ForOwnedRepo(fullName string) TargetRepo {
+	return TargetRepo{Name: fullName, Tags: t.Tags}
+}
+
+// Label returns the name to show for the repository.
+func (t TargetRepo) Label() string {
+	if t.DisplayName != "" {
+		return t.DisplayName
+	}
+	return t.Name
+}
+
+// HasTag reports whether the repository carries tag, ignoring case.
+func (t TargetRepo) HasTag(tag string) bool {
+	return slices.ContainsFunc(t.Tags, func(t string) bool { return strings.EqualFold(t, tag) })
+}
+
+// AddTags adds the given tags that the repository doesn't carry yet.
+func (t *TargetRepo) AddTags(tags ...string) {
+	for _, tag := range tags {
+		if tag != "" && !t.HasTag(tag) {
+			t.Tags = append(t.Tags, tag)
+		}
+	}
+}
+
+// hasMetadata reports whether any field other than Name is set.
+func (t TargetRepo) hasMetadata() bool {
+	return t.DisplayName != "" || len(t.Tags) > 0 || t.Notes != "" || t.Disabled ||
+		t.Language != "" || t.MinStars != 0 || t.IncludeForks || t.IncludeArchived
+}
+
+// MarshalJSON writes entries without metadata in the plain string form,
+// so that simple lists stay compatible with older versions.
+func (t TargetRepo) MarshalJSON() ([]byte, error) {
+	if !t.hasMetadata() {
+		return json.Marshal(t.Name)
+	}
+	type plain TargetRepo // Avoids recursing into MarshalJSON.
+	return json.Marshal(plain(t))
+}
+
+// UnmarshalJSON accepts both the plain string form and the object form.
+func (t *TargetRepo) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(`"`)) {
+		*t = TargetRepo{}
+		return json.Unmarshal(data, &t.Name)
+	}
+
+	type plain TargetRepo // Avoids recursing into UnmarshalJSON.
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	if p.Name == "" {
+		return fmt.Errorf("repository entry without a name: %s", data)
+	}
+	*t = TargetRepo(p)
+	return nil
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetRepo_JSON(t *testing.T) {
+	t.Parallel()
+
+	var targets []TargetRepo
+	input := `["owner/plain", {"name": "owner/rich", "display_name": "Rich", "tags": ["web"], "notes": "n", "disabled": true}]`
+	require.NoError(t, json.Unmarshal([]byte(input), &targets))
+	assert.Equal(t, []TargetRepo{
+		{Name: "owner/plain"},
+		{Name: "owner/rich", DisplayName: "Rich", Tags: []string{"web"}, Notes: "n", Disabled: true},
+	}, targets)
+
+	output, err := json.Marshal(targets)
+	require.NoError(t, err)
+	assert.JSONEq(t, input, string(output))
+
+	err = json.Unmarshal([]byte(`[{"tags": ["web"]}]`), &targets)
+	require.Error(t, err)
+}
+
+func TestTargetRepo_Tags(t *testing.T) {
+	t.Parallel()
+
+	target := TargetRepo{Name: "owner/repo", Tags: []string{"Web"}}
+	assert.True(t, target.HasTag("web"))
+	assert.False(t, target.HasTag("orm"))
+
+	target.AddTags("WEB", "orm", "")
+	assert.Equal(t, []string{"Web", "orm"}, target.Tags)
+	assert.Equal(t, "owner/repo", target.Label())
+}
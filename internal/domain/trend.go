@@ -19,6 +19,16 @@ type Trend struct {
 	Diff int
 	// Period is the time period of the trend calculation.
 	Period TrendPeriod
+	// Target is the entry of the repository in the tracked list, if it is still listed.
+	Target *TargetRepo
+}
+
+// Label returns the name to show for the trend's repository.
+func (t *Trend) Label() string {
+	if t.Target != nil {
+		return t.Target.Label()
+	}
+	return t.Repository.FullName
 }
 
 // NewTrend creates a new Trend object.
@@ -0,0 +1,150 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/v79/github"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// appJWTLifetime is how long a minted app JWT is valid. GitHub allows at most 10 minutes.
+	appJWTLifetime = 9 * time.Minute
+	// appJWTClockSkew backdates the issue time to tolerate clock drift between us and GitHub.
+	appJWTClockSkew = time.Minute
+)
+
+// loadAppPrivateKey reads a GitHub App private key in PEM format (PKCS#1 or PKCS#8).
+func loadAppPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read GitHub App private key '%s': %w", path, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in GitHub App private key '%s'", path)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse GitHub App private key '%s': %w", path, err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("GitHub App private key '%s' is not an RSA key", path)
+	}
+	return key, nil
+}
+
+// appJWTSource mints the short-lived JWTs that authenticate as the GitHub App itself.
+type appJWTSource struct {
+	appID int64
+	key   *rsa.PrivateKey
+	now   func() time.Time
+}
+
+// Token implements oauth2.TokenSource by signing a new RS256 JWT.
+func (s *appJWTSource) Token() (*oauth2.Token, error) {
+	now := s.now()
+	expiry := now.Add(appJWTLifetime)
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return nil, err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-appJWTClockSkew).Unix(),
+		"exp": expiry.Unix(),
+		"iss": strconv.FormatInt(s.appID, 10),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("could not sign GitHub App JWT: %w", err)
+	}
+
+	return &oauth2.Token{
+		AccessToken: signingInput + "." + base64.RawURLEncoding.EncodeToString(signature),
+		TokenType:   "Bearer",
+		Expiry:      expiry,
+	}, nil
+}
+
+// installationTokenSource exchanges app JWTs for installation access tokens.
+// Wrap it with oauth2.ReuseTokenSource so that a token is only minted when the previous one expires.
+type installationTokenSource struct {
+	// appClient is authenticated with app JWTs.
+	appClient      *github.Client
+	installationID int64
+	logger         *slog.Logger
+}
+
+// Token implements oauth2.TokenSource by creating a new installation access token.
+func (s *installationTokenSource) Token() (*oauth2.Token, error) {
+	s.logger.Debug("Creating GitHub App installation token", "installation_id", s.installationID)
+
+	token, _, err := s.appClient.Apps.CreateInstallationToken(context.Background(), s.installationID, nil)
+	if err != nil {
+		s.logger.Error("Failed to create GitHub App installation token", "installation_id", s.installationID, "error", err)
+		return nil, fmt.Errorf("could not create installation token for installation %d: %w", s.installationID, err)
+	}
+	if token.GetToken() == "" {
+		return nil, errors.New("github returned an empty installation token")
+	}
+
+	s.logger.Info("Created GitHub App installation token", "installation_id", s.installationID, "expires_at", token.GetExpiresAt().Time)
+	return &oauth2.Token{
+		AccessToken: token.GetToken(),
+		TokenType:   "token",
+		Expiry:      token.GetExpiresAt().Time,
+	}, nil
+}
+
+// newAppTokenSource returns a token source that authenticates as a GitHub App installation,
+// minting and refreshing JWTs and installation tokens automatically.
+// Installation tokens are created with the GitHub client that newClient builds around an app-authenticated HTTP client.
+func newAppTokenSource(appID, installationID int64, keyPath string, base http.RoundTripper,
+	newClient func(*http.Client) (*github.Client, error), logger *slog.Logger,
+) (oauth2.TokenSource, error) {
+	key, err := loadAppPrivateKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	jwts := &appJWTSource{appID: appID, key: key, now: time.Now}
+	appClient, err := newClient(&http.Client{
+		Transport: &oauth2.Transport{Source: oauth2.ReuseTokenSource(nil, jwts), Base: base},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return oauth2.ReuseTokenSource(nil, &installationTokenSource{
+		appClient:      appClient,
+		installationID: installationID,
+		logger:         logger.With("component", "github_app_auth"),
+	}), nil
+}
@@ -0,0 +1,125 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v79/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// writeTestAppKey generates an RSA key and writes it as a PKCS#1 PEM file.
+func writeTestAppKey(t *testing.T) (*rsa.PrivateKey, string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "app.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	require.NoError(t, os.WriteFile(path, data, 0600))
+	return key, path
+}
+
+// verifyTestJWT checks the signature of an RS256 JWT and returns its claims.
+func verifyTestJWT(t *testing.T, token string, pub *rsa.PublicKey) map[string]any {
+	t.Helper()
+	parts := strings.Split(token, ".")
+	require.Len(t, parts, 3)
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	require.NoError(t, rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature))
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var claims map[string]any
+	require.NoError(t, json.Unmarshal(payload, &claims))
+	return claims
+}
+
+func TestAppJWTSource_Token(t *testing.T) {
+	t.Parallel()
+	key, path := writeTestAppKey(t)
+	loaded, err := loadAppPrivateKey(path)
+	require.NoError(t, err)
+
+	now := time.Date(2025, 11, 22, 12, 0, 0, 0, time.UTC)
+	token, err := (&appJWTSource{appID: 1234, key: loaded, now: func() time.Time { return now }}).Token()
+	require.NoError(t, err)
+
+	claims := verifyTestJWT(t, token.AccessToken, &key.PublicKey)
+	assert.Equal(t, "1234", claims["iss"])
+	assert.Equal(t, float64(now.Add(-appJWTClockSkew).Unix()), claims["iat"])
+	assert.Equal(t, float64(now.Add(appJWTLifetime).Unix()), claims["exp"])
+}
+
+func TestLoadAppPrivateKey_Invalid(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "bad.pem")
+	require.NoError(t, os.WriteFile(path, []byte("not a key"), 0600))
+
+	_, err := loadAppPrivateKey(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no PEM data found")
+}
+
+func TestAppTokenSource_InstallationTokens(t *testing.T) {
+	t.Parallel()
+	key, path := writeTestAppKey(t)
+
+	var minted atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/app/installations/42/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		jwt, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		require.True(t, ok)
+		assert.Equal(t, "7", verifyTestJWT(t, jwt, &key.PublicKey)["iss"])
+
+		n := minted.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "ghs_%d", "expires_at": %q}`, n, time.Now().Add(time.Hour).Format(time.RFC3339))
+	})
+	mux.HandleFunc("/api/v3/repos/owner/repo", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token ghs_1", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"stargazers_count": 3}`)
+	})
+	server, transport := newTestServer(t, mux)
+
+	newClient := func(httpClient *http.Client) (*github.Client, error) {
+		return github.NewClient(httpClient).WithEnterpriseURLs(server.URL, server.URL)
+	}
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+	ts, err := newAppTokenSource(7, 42, path, transport, newClient, logger)
+	require.NoError(t, err)
+
+	ghClient := newTestGitHubClient(t, server, &oauth2.Transport{Source: ts, Base: transport})
+	client := &Client{client: ghClient, logger: logger}
+
+	for range 2 {
+		repo, err := client.FetchStars(context.Background(), "owner/repo")
+		require.NoError(t, err)
+		assert.Equal(t, 3, repo.Stars)
+	}
+	assert.Equal(t, int32(1), minted.Load(), "the installation token should be reused until it expires")
+}
@@ -2,8 +2,12 @@ package github
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v79/github"
 	"golang.org/x/oauth2"
@@ -12,51 +16,167 @@ import (
 	"github.com/yourname/go-trendboard/internal/domain"
 )
 
+// UnauthenticatedRateLimit is the number of requests per hour GitHub allows without credentials.
+const UnauthenticatedRateLimit = 60
+
 // Client is a GitHub API client that implements the Fetcher interface.
 type Client struct {
-	client *github.Client
-	logger *slog.Logger
+	client  *github.Client
+	limiter *RateLimiter
+	logger  *slog.Logger
 }
 
 // NewClient creates a new instance of the GitHub API client.
-// It requires a configuration object for the API token and a logger.
-func NewClient(cfg *config.Config, logger *slog.Logger) *Client {
-	ctx := context.Background()
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: cfg.GitHubToken},
-	)
-	tc := oauth2.NewClient(ctx, ts)
-	client := github.NewClient(tc)
+// It requires a configuration object for the API credentials and a logger.
+func NewClient(cfg *config.Config, logger *slog.Logger) (*Client, error) {
+	httpClient, err := newHTTPClient(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newGitHubClient(httpClient, cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	return &Client{
-		client: client,
-		logger: logger.With("component", "github_client"),
+		client:  client,
+		limiter: NewRateLimiter(cfg, logger),
+		logger:  logger.With("component", "github_client"),
+	}, nil
+}
+
+// newGitHubClient creates a go-github client around httpClient that talks to
+// the configured GitHub Enterprise Server, or to the public GitHub API by default.
+func newGitHubClient(httpClient *http.Client, cfg *config.Config) (*github.Client, error) {
+	client := github.NewClient(httpClient)
+	if cfg.GitHubBaseURL == "" {
+		return client, nil
+	}
+
+	uploadURL := cfg.GitHubUploadURL
+	if uploadURL == "" {
+		uploadURL = cfg.GitHubBaseURL
+	}
+	client, err := client.WithEnterpriseURLs(cfg.GitHubBaseURL, uploadURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GitHub Enterprise URL '%s': %w", cfg.GitHubBaseURL, err)
+	}
+	return client, nil
+}
+
+// newHTTPClient builds the authenticated HTTP client used for all GitHub API requests,
+// with an on-disk cache for conditional requests when a cache directory is configured.
+func newHTTPClient(cfg *config.Config, logger *slog.Logger) (*http.Client, error) {
+	var transport http.RoundTripper = http.DefaultTransport
+	if cfg.HTTPCacheDir != "" {
+		transport = newCacheTransport(transport, cfg.HTTPCacheDir, logger)
+	}
+
+	if !cfg.HasCredentials() {
+		logger.Warn("No GitHub credentials configured, using unauthenticated requests limited to 60 per hour",
+			"limit", UnauthenticatedRateLimit)
+		return &http.Client{Transport: transport}, nil
+	}
+
+	// Several personal access tokens are rotated by a pool instead of a single token source.
+	// ValidateGitHubAuth rejects combining them with a GitHub App.
+	if len(cfg.GitHubTokens) > 0 && cfg.GitHubAppID == 0 {
+		tokens := append([]string{cfg.GitHubToken}, cfg.GitHubTokens...)
+		pool, err := newTokenPoolTransport(transport, tokens, cfg.GitHubTokenStrategy, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set up token pool: %w", err)
+		}
+		return &http.Client{Transport: pool}, nil
+	}
+
+	ts, err := newTokenSource(cfg, logger)
+	if err != nil {
+		return nil, err
 	}
+	return &http.Client{
+		Transport: &oauth2.Transport{Source: ts, Base: transport},
+	}, nil
+}
+
+// newTokenSource returns the source of API credentials: GitHub App installation tokens
+// when an app is configured, and the personal access token otherwise.
+func newTokenSource(cfg *config.Config, logger *slog.Logger) (oauth2.TokenSource, error) {
+	if cfg.GitHubAppID != 0 {
+		newClient := func(httpClient *http.Client) (*github.Client, error) {
+			return newGitHubClient(httpClient, cfg)
+		}
+		ts, err := newAppTokenSource(cfg.GitHubAppID, cfg.GitHubAppInstallationID, cfg.GitHubAppPrivateKeyPath,
+			http.DefaultTransport, newClient, logger)
+		if err != nil {
+			logger.Error("Failed to set up GitHub App authentication", "app_id", cfg.GitHubAppID, "error", err)
+			return nil, fmt.Errorf("failed to set up GitHub App authentication: %w", err)
+		}
+		return ts, nil
+	}
+
+	return oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: cfg.GitHubToken},
+	), nil
 }
 
 // FetchStars fetches the star count for a given repository from the GitHub API.
 func (c *Client) FetchStars(ctx context.Context, repoName string) (*domain.Repository, error) {
 	c.logger.Debug("Fetching stars", "repo", repoName)
 
-	parts := strings.Split(repoName, "/")
-	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+	owner, repo, ok := splitRepoName(repoName)
+	if !ok {
 		return domain.NewRepository(repoName, 0)
 	}
-	owner, repo := parts[0], parts[1]
 
-	ghRepo, resp, err := c.client.Repositories.Get(ctx, owner, repo)
+	ghRepo, err := c.getRepository(ctx, owner, repo)
 	if err != nil {
-		// Handle rate limit error specifically
-		if _, ok := err.(*github.RateLimitError); ok {
-			c.logger.Warn("GitHub API rate limit exceeded", "repo", repoName, "response", resp)
-			return nil, &RateLimitError{repoName: repoName, cause: err}
+		// Handle rate limit errors specifically
+		var rateErr *github.RateLimitError
+		var abuseErr *github.AbuseRateLimitError
+		switch {
+		case errors.As(err, &rateErr):
+			c.logger.Warn("GitHub API rate limit exceeded", "repo", repoName, "reset", rateErr.Rate.Reset.Time)
+			return nil, &RateLimitError{repoName: repoName, resetAt: rateErr.Rate.Reset.Time, cause: err}
+		case errors.As(err, &abuseErr):
+			c.logger.Warn("GitHub API secondary rate limit exceeded", "repo", repoName, "retry_after", abuseErr.GetRetryAfter())
+			return nil, &RateLimitError{repoName: repoName, resetAt: time.Now().Add(abuseErr.GetRetryAfter()), cause: err}
 		}
+		var respErr *github.ErrorResponse
+		notFound := errors.As(err, &respErr) && respErr.Response != nil && respErr.Response.StatusCode == http.StatusNotFound
 		c.logger.Error("Failed to fetch repository from GitHub API", "repo", repoName, "error", err)
-		return nil, &FetchError{repoName: repoName, cause: err}
+		return nil, &FetchError{repoName: repoName, notFound: notFound, cause: err}
 	}
 
 	stars := ghRepo.GetStargazersCount()
 	c.logger.Debug("Successfully fetched stars", "repo", repoName, "stars", stars)
 
-	return domain.NewRepository(repoName, stars)
+	// GitHub follows renames and transfers with a redirect; the response carries the canonical name.
+	return domain.NewFetchedRepository(repoName, ghRepo.GetFullName(), ghRepo.GetID(), stars)
+}
+
+// getRepository fetches a repository, throttling as the quota drains and
+// retrying after rate limits as permitted by the rate limiter.
+func (c *Client) getRepository(ctx context.Context, owner, repo string) (*github.Repository, error) {
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		ghRepo, resp, err := c.client.Repositories.Get(ctx, owner, repo)
+		if resp != nil {
+			c.limiter.Observe(resp.Rate)
+		}
+		if err == nil || attempt >= maxRateLimitRetries || !c.limiter.Backoff(ctx, err) {
+			return ghRepo, err
+		}
+	}
+}
+
+// splitRepoName splits an "owner/name" repository name into its parts.
+func splitRepoName(repoName string) (owner, name string, ok bool) {
+	parts := strings.Split(repoName, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
 }
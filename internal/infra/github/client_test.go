@@ -12,15 +12,35 @@ import (
 	"github.com/google/go-github/v79/github"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/yourname/go-trendboard/internal/domain"
 )
 
+// newTestServer starts a test server for handler, closed at the end of the test, and returns it
+// with the transport that clients of the server should send their requests through. That is the
+// server's own transport rather than http.DefaultTransport, so that tests running in parallel
+// don't share, and wait on, the default transport's connections.
+func newTestServer(t *testing.T, handler http.Handler) (*httptest.Server, http.RoundTripper) {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server, server.Client().Transport
+}
+
+// newTestGitHubClient creates a GitHub client that sends its requests to server through transport.
+func newTestGitHubClient(t *testing.T, server *httptest.Server, transport http.RoundTripper) *github.Client {
+	t.Helper()
+	ghClient, err := github.NewClient(&http.Client{Transport: transport}).WithEnterpriseURLs(server.URL, server.URL)
+	require.NoError(t, err)
+	return ghClient
+}
+
 // setupTestClient sets up a test HTTP server and a GitHub client pointing to it.
 func setupTestClient(t *testing.T, handler http.Handler) (*Client, *http.ServeMux) {
 	t.Helper()
 
 	mux := http.NewServeMux()
-	server := httptest.NewServer(mux)
-	t.Cleanup(server.Close)
+	server, transport := newTestServer(t, mux)
 
 	// The handler passed from the test is registered on the mux
 	if handler != nil {
@@ -29,12 +49,8 @@ func setupTestClient(t *testing.T, handler http.Handler) (*Client, *http.ServeMu
 
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
 
-	// Create a client and override the base URL to point to the test server
-	ghClient, err := github.NewClient(nil).WithEnterpriseURLs(server.URL, server.URL)
-	require.NoError(t, err)
-
 	client := &Client{
-		client: ghClient,
+		client: newTestGitHubClient(t, server, transport),
 		logger: logger,
 	}
 
@@ -63,6 +79,26 @@ func TestClient_FetchStars(t *testing.T) {
 		assert.Equal(t, expectedStars, repo.Stars)
 	})
 
+	t.Run("Renamed", func(t *testing.T) {
+		t.Parallel()
+		client, mux := setupTestClient(t, nil)
+
+		mux.HandleFunc("/api/v3/repos/old-owner/old-name", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"id": 42, "full_name": "new-owner/new-name", "stargazers_count": 7}`)
+		})
+
+		repo, err := client.FetchStars(context.Background(), "old-owner/old-name")
+		require.NoError(t, err)
+		assert.Equal(t, &domain.Repository{
+			ID:          42,
+			FullName:    "new-owner/new-name",
+			Stars:       7,
+			Status:      domain.StatusRenamed,
+			RenamedFrom: "old-owner/old-name",
+		}, repo)
+	})
+
 	t.Run("Not Found", func(t *testing.T) {
 		t.Parallel()
 		client, mux := setupTestClient(t, nil)
@@ -78,6 +114,7 @@ func TestClient_FetchStars(t *testing.T) {
 		assert.Nil(t, repo)
 		var fetchErr *FetchError
 		assert.ErrorAs(t, err, &fetchErr)
+		assert.ErrorIs(t, err, ErrNotFound)
 	})
 
 	t.Run("Rate Limited", func(t *testing.T) {
@@ -1,10 +1,18 @@
 package github
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotFound is matched by fetch errors for repositories that do not exist or are not accessible.
+var ErrNotFound = errors.New("repository not found")
 
 // RateLimitError is returned when the GitHub API rate limit is exceeded.
 type RateLimitError struct {
 	repoName string
+	resetAt  time.Time
 	cause    error
 }
 
@@ -16,9 +24,15 @@ func (e *RateLimitError) Unwrap() error {
 	return e.cause
 }
 
+// ResetAt returns the time at which the rate limit resets, or the zero time if it is unknown.
+func (e *RateLimitError) ResetAt() time.Time {
+	return e.resetAt
+}
+
 // FetchError is returned for general errors during GitHub API fetches.
 type FetchError struct {
 	repoName string
+	notFound bool
 	cause    error
 }
 
@@ -29,3 +43,9 @@ func (e *FetchError) Error() string {
 func (e *FetchError) Unwrap() error {
 	return e.cause
 }
+
+// Is reports whether the error matches target, so that errors.Is(err, ErrNotFound)
+// identifies repositories that do not exist.
+func (e *FetchError) Is(target error) bool {
+	return target == ErrNotFound && e.notFound
+}
@@ -2,7 +2,10 @@ package github
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 
+	"github.com/yourname/go-trendboard/internal/config"
 	"github.com/yourname/go-trendboard/internal/domain"
 )
 
@@ -12,3 +15,53 @@ type Fetcher interface {
 	// The repoName is expected to be in "owner/name" format.
 	FetchStars(ctx context.Context, repoName string) (*domain.Repository, error)
 }
+
+// BatchFetcher is implemented by fetchers that can fetch many repositories in a single request.
+type BatchFetcher interface {
+	Fetcher
+
+	// FetchStarsBatch fetches the star counts for the given repositories.
+	// It returns one result per repository, in the same order as repoNames.
+	FetchStarsBatch(ctx context.Context, repoNames []string) []FetchResult
+}
+
+// FetchResult is the outcome of fetching a single repository as part of a batch.
+type FetchResult struct {
+	// RepoName is the repository name as it was requested.
+	RepoName string
+	// Repository is the fetched repository, or nil if Err is set.
+	Repository *domain.Repository
+	// Err is the error that occurred while fetching the repository, if any.
+	Err error
+}
+
+// NewFetcher is a factory function that returns the appropriate fetcher
+// based on the configuration, wrapped with the configured retry policy.
+func NewFetcher(cfg *config.Config, logger *slog.Logger) (Fetcher, error) {
+	var (
+		fetcher Fetcher
+		err     error
+	)
+	kind := cfg.Fetcher
+	if kind == "graphql" && !cfg.HasCredentials() {
+		// The GraphQL API rejects unauthenticated requests.
+		logger.Warn("GraphQL fetcher requires credentials, falling back to REST")
+		kind = "rest"
+	}
+	switch kind {
+	case "", "rest":
+		fetcher, err = NewClient(cfg, logger)
+	case "graphql":
+		fetcher, err = NewGraphQLClient(cfg, logger)
+	default:
+		return nil, fmt.Errorf("unknown fetcher: %s", cfg.Fetcher)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.RetryMaxAttempts > 1 {
+		fetcher = NewRetryFetcher(fetcher, NewRetryPolicy(cfg), logger)
+	}
+	return fetcher, nil
+}
@@ -0,0 +1,215 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v79/github"
+
+	"github.com/yourname/go-trendboard/internal/config"
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+const (
+	// graphqlEndpoint is the GraphQL endpoint, relative to the REST API base URL.
+	graphqlEndpoint = "graphql"
+	// defaultGraphQLBatchSize is used when no valid batch size is configured.
+	defaultGraphQLBatchSize = 50
+	// maxGraphQLBatchSize keeps a single query well below GitHub's node limits.
+	maxGraphQLBatchSize = 100
+)
+
+// GraphQLClient is a GitHub GraphQL API client that implements the BatchFetcher interface.
+// It fetches many repositories per query using aliases and falls back to the REST API
+// for batches or repositories that the GraphQL API could not serve.
+type GraphQLClient struct {
+	client    *github.Client
+	endpoint  string
+	batchSize int
+	limiter   *RateLimiter
+	fallback  Fetcher
+	logger    *slog.Logger
+}
+
+// NewGraphQLClient creates a new instance of the GitHub GraphQL API client.
+// A REST client built from the same configuration is used as the fallback.
+func NewGraphQLClient(cfg *config.Config, logger *slog.Logger) (*GraphQLClient, error) {
+	rest, err := NewClient(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := cfg.GraphQLBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultGraphQLBatchSize
+	}
+	if batchSize > maxGraphQLBatchSize {
+		batchSize = maxGraphQLBatchSize
+	}
+
+	return &GraphQLClient{
+		client:    rest.client,
+		endpoint:  graphqlURL(rest.client),
+		batchSize: batchSize,
+		limiter:   NewRateLimiter(cfg, logger), // GraphQL has its own quota, separate from REST.
+		fallback:  rest,
+		logger:    logger.With("component", "github_graphql_client"),
+	}, nil
+}
+
+// graphqlURL returns the GraphQL endpoint for client. GitHub Enterprise Server serves
+// the REST API under /api/v3/ but the GraphQL API under /api/graphql.
+func graphqlURL(client *github.Client) string {
+	if base := client.BaseURL; strings.HasSuffix(base.Path, "/api/v3/") {
+		return base.ResolveReference(&url.URL{Path: "../graphql"}).String()
+	}
+	return graphqlEndpoint
+}
+
+// FetchStars fetches the star count for a single repository.
+func (c *GraphQLClient) FetchStars(ctx context.Context, repoName string) (*domain.Repository, error) {
+	result := c.FetchStarsBatch(ctx, []string{repoName})[0]
+	return result.Repository, result.Err
+}
+
+// FetchStarsBatch fetches the star counts for the given repositories, batchSize repositories per query.
+func (c *GraphQLClient) FetchStarsBatch(ctx context.Context, repoNames []string) []FetchResult {
+	results := make([]FetchResult, len(repoNames))
+	for start := 0; start < len(repoNames); start += c.batchSize {
+		end := min(start+c.batchSize, len(repoNames))
+		c.fetchBatch(ctx, repoNames[start:end], results[start:end])
+	}
+	return results
+}
+
+// graphqlRepository is the subset of the GraphQL Repository object requested per alias.
+type graphqlRepository struct {
+	NameWithOwner  string `json:"nameWithOwner"`
+	StargazerCount int    `json:"stargazerCount"`
+	DatabaseID     int64  `json:"databaseId"`
+}
+
+// graphqlError is a single entry of the "errors" array of a GraphQL response.
+type graphqlError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Path    []any  `json:"path"`
+}
+
+func (e graphqlError) Error() string {
+	if e.Type == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Type, e.Message)
+}
+
+// graphqlResponse is the envelope of a GraphQL response.
+type graphqlResponse struct {
+	Data   map[string]*graphqlRepository `json:"data"`
+	Errors []graphqlError                `json:"errors"`
+}
+
+// fetchBatch fetches a single batch and writes one result per repository into results.
+func (c *GraphQLClient) fetchBatch(ctx context.Context, repoNames []string, results []FetchResult) {
+	c.logger.Debug("Fetching stars via GraphQL", "count", len(repoNames))
+
+	// Invalid names are rejected up front, exactly as the REST client does,
+	// so that a single bad entry can't fail the whole query.
+	aliases := make(map[string]int, len(repoNames))
+	var (
+		params []string
+		fields []string
+		vars   = make(map[string]any, 2*len(repoNames))
+	)
+	for i, repoName := range repoNames {
+		results[i].RepoName = repoName
+		owner, name, ok := splitRepoName(repoName)
+		if !ok {
+			results[i].Repository, results[i].Err = domain.NewRepository(repoName, 0)
+			continue
+		}
+		alias := fmt.Sprintf("r%d", i)
+		aliases[alias] = i
+		params = append(params, fmt.Sprintf("$o%d: String!, $n%d: String!", i, i))
+		fields = append(fields, fmt.Sprintf("%s: repository(owner: $o%d, name: $n%d) { nameWithOwner stargazerCount databaseId }", alias, i, i))
+		vars[fmt.Sprintf("o%d", i)] = owner
+		vars[fmt.Sprintf("n%d", i)] = name
+	}
+	if len(aliases) == 0 {
+		return
+	}
+
+	query := fmt.Sprintf("query(%s) {\n%s\n}", strings.Join(params, ", "), strings.Join(fields, "\n"))
+	resp, err := c.query(ctx, query, vars)
+	if err != nil {
+		c.logger.Warn("GraphQL batch query failed, falling back to REST", "count", len(aliases), "error", err)
+		for _, i := range aliases {
+			results[i].Repository, results[i].Err = c.fallback.FetchStars(ctx, repoNames[i])
+		}
+		return
+	}
+
+	errorsByAlias := make(map[string]graphqlError, len(resp.Errors))
+	for _, gqlErr := range resp.Errors {
+		if len(gqlErr.Path) > 0 {
+			if alias, ok := gqlErr.Path[0].(string); ok {
+				errorsByAlias[alias] = gqlErr
+			}
+		}
+	}
+
+	for alias, i := range aliases {
+		repoName := repoNames[i]
+		if ghRepo := resp.Data[alias]; ghRepo != nil {
+			c.logger.Debug("Successfully fetched stars", "repo", repoName, "stars", ghRepo.StargazerCount)
+			results[i].Repository, results[i].Err = domain.NewFetchedRepository(repoName, ghRepo.NameWithOwner, ghRepo.DatabaseID, ghRepo.StargazerCount)
+			continue
+		}
+
+		gqlErr, ok := errorsByAlias[alias]
+		if ok && gqlErr.Type == "NOT_FOUND" {
+			c.logger.Error("Repository not found via GraphQL API", "repo", repoName, "error", gqlErr)
+			results[i].Err = &FetchError{repoName: repoName, notFound: true, cause: gqlErr}
+			continue
+		}
+
+		c.logger.Warn("GraphQL returned no data for repository, falling back to REST", "repo", repoName)
+		results[i].Repository, results[i].Err = c.fallback.FetchStars(ctx, repoName)
+	}
+}
+
+// query sends a GraphQL query and decodes the response.
+// Requests that fail as a whole, including responses that carry errors but no data, return an error.
+func (c *GraphQLClient) query(ctx context.Context, query string, vars map[string]any) (*graphqlResponse, error) {
+	body := map[string]any{"query": query, "variables": vars}
+
+	var resp graphqlResponse
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		// The request is rebuilt on every attempt since its body is consumed when sent.
+		req, err := c.client.NewRequest(http.MethodPost, c.endpoint, body)
+		if err != nil {
+			return nil, fmt.Errorf("could not build GraphQL request: %w", err)
+		}
+		ghResp, err := c.client.Do(ctx, req, &resp)
+		if ghResp != nil {
+			c.limiter.Observe(ghResp.Rate)
+		}
+		if err == nil {
+			break
+		}
+		if attempt >= maxRateLimitRetries || !c.limiter.Backoff(ctx, err) {
+			return nil, err
+		}
+	}
+	if resp.Data == nil && len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("graphql query failed: %w", resp.Errors[0])
+	}
+	return &resp, nil
+}
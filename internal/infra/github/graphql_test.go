@@ -0,0 +1,167 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourname/go-trendboard/internal/config"
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+// setupTestGraphQLClient sets up a GraphQL client whose fallback is a REST client on the same test server.
+func setupTestGraphQLClient(t *testing.T, batchSize int) (*GraphQLClient, *http.ServeMux) {
+	t.Helper()
+	rest, mux := setupTestClient(t, nil)
+
+	client := &GraphQLClient{
+		client:    rest.client,
+		endpoint:  graphqlEndpoint,
+		batchSize: batchSize,
+		fallback:  rest,
+		logger:    rest.logger,
+	}
+	return client, mux
+}
+
+// decodeGraphQLVariables decodes the variables of a GraphQL request body.
+func decodeGraphQLVariables(t *testing.T, r *http.Request) map[string]string {
+	t.Helper()
+	var body struct {
+		Query     string            `json:"query"`
+		Variables map[string]string `json:"variables"`
+	}
+	require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+	return body.Variables
+}
+
+func TestGraphQLClient_FetchStarsBatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Success in batches", func(t *testing.T) {
+		t.Parallel()
+		client, mux := setupTestGraphQLClient(t, 2)
+		stars := map[string]int{"owner/repo1": 10, "owner/repo2": 20, "owner/repo3": 30}
+
+		var queries atomic.Int32
+		mux.HandleFunc("/api/v3/graphql", func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			queries.Add(1)
+			vars := decodeGraphQLVariables(t, r)
+
+			data := map[string]any{}
+			for i := 0; ; i++ {
+				owner, ok := vars[fmt.Sprintf("o%d", i)]
+				if !ok {
+					break
+				}
+				name := vars[fmt.Sprintf("n%d", i)]
+				data[fmt.Sprintf("r%d", i)] = map[string]any{
+					"nameWithOwner":  owner + "/" + name,
+					"stargazerCount": stars[owner+"/"+name],
+				}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]any{"data": data}))
+		})
+
+		results := client.FetchStarsBatch(context.Background(), []string{"owner/repo1", "owner/repo2", "owner/repo3"})
+		require.Len(t, results, 3)
+		assert.Equal(t, int32(2), queries.Load())
+		for _, result := range results {
+			require.NoError(t, result.Err)
+			assert.Equal(t, result.RepoName, result.Repository.FullName)
+			assert.Equal(t, stars[result.RepoName], result.Repository.Stars)
+		}
+	})
+
+	t.Run("Not Found alias", func(t *testing.T) {
+		t.Parallel()
+		client, mux := setupTestGraphQLClient(t, 10)
+
+		mux.HandleFunc("/api/v3/graphql", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{
+				"data": {"r0": {"nameWithOwner": "owner/repo", "stargazerCount": 5}, "r1": null},
+				"errors": [{"type": "NOT_FOUND", "path": ["r1"], "message": "Could not resolve to a Repository"}]
+			}`)
+		})
+
+		results := client.FetchStarsBatch(context.Background(), []string{"owner/repo", "owner/missing"})
+		require.NoError(t, results[0].Err)
+		assert.Equal(t, 5, results[0].Repository.Stars)
+
+		require.Error(t, results[1].Err)
+		assert.Nil(t, results[1].Repository)
+		var fetchErr *FetchError
+		assert.ErrorAs(t, results[1].Err, &fetchErr)
+		assert.ErrorIs(t, results[1].Err, ErrNotFound)
+	})
+
+	t.Run("Renamed", func(t *testing.T) {
+		t.Parallel()
+		client, mux := setupTestGraphQLClient(t, 10)
+
+		mux.HandleFunc("/api/v3/graphql", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"data": {"r0": {"nameWithOwner": "new-owner/new-name", "stargazerCount": 5, "databaseId": 42}}}`)
+		})
+
+		results := client.FetchStarsBatch(context.Background(), []string{"old-owner/old-name"})
+		require.NoError(t, results[0].Err)
+		assert.Equal(t, "old-owner/old-name", results[0].RepoName)
+		assert.Equal(t, "new-owner/new-name", results[0].Repository.FullName)
+		assert.Equal(t, int64(42), results[0].Repository.ID)
+		assert.Equal(t, domain.StatusRenamed, results[0].Repository.Status)
+		assert.Equal(t, "old-owner/old-name", results[0].Repository.RenamedFrom)
+	})
+
+	t.Run("Falls back to REST on query failure", func(t *testing.T) {
+		t.Parallel()
+		client, mux := setupTestGraphQLClient(t, 10)
+
+		mux.HandleFunc("/api/v3/graphql", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		})
+		mux.HandleFunc("/api/v3/repos/owner/repo", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"stargazers_count": 42}`)
+		})
+
+		results := client.FetchStarsBatch(context.Background(), []string{"owner/repo"})
+		require.NoError(t, results[0].Err)
+		assert.Equal(t, 42, results[0].Repository.Stars)
+	})
+
+	t.Run("Invalid Repo Name", func(t *testing.T) {
+		t.Parallel()
+		client, _ := setupTestGraphQLClient(t, 10)
+
+		results := client.FetchStarsBatch(context.Background(), []string{"invalid-repo-name"})
+		require.Error(t, results[0].Err)
+		assert.Nil(t, results[0].Repository)
+		assert.Contains(t, results[0].Err.Error(), "invalid repository full name format")
+	})
+}
+
+func TestNewGraphQLClient_EnterpriseEndpoint(t *testing.T) {
+	t.Parallel()
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+
+	public, err := NewGraphQLClient(&config.Config{GitHubToken: "token"}, logger)
+	require.NoError(t, err)
+	assert.Equal(t, "graphql", public.endpoint)
+
+	ghes, err := NewGraphQLClient(&config.Config{GitHubToken: "token", GitHubBaseURL: "https://ghes.example.com/"}, logger)
+	require.NoError(t, err)
+	assert.Equal(t, "https://ghes.example.com/api/v3/", ghes.client.BaseURL.String())
+	assert.Equal(t, "https://ghes.example.com/api/graphql", ghes.endpoint)
+}
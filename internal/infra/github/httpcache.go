@@ -0,0 +1,186 @@
+package github
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// cacheStatusHeader is set on responses served from the cache after a successful revalidation.
+const cacheStatusHeader = "X-Trendboard-Cache"
+
+// cacheEntry is a cached response, stored as one JSON file per request.
+type cacheEntry struct {
+	URL          string      `json:"url"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+}
+
+// cacheTransport is an http.RoundTripper that stores GET responses carrying an ETag or
+// Last-Modified validator on disk and revalidates them with conditional requests.
+// GitHub does not count 304 Not Modified responses against the primary rate limit.
+type cacheTransport struct {
+	base   http.RoundTripper
+	dir    string
+	logger *slog.Logger
+}
+
+// newCacheTransport creates a cacheTransport that stores its entries in dir.
+func newCacheTransport(base http.RoundTripper, dir string, logger *slog.Logger) *cacheTransport {
+	return &cacheTransport{
+		base:   base,
+		dir:    dir,
+		logger: logger.With("component", "http_cache"),
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	path := t.entryPath(req)
+	entry := t.load(path)
+	if entry != nil {
+		// RoundTrippers must not modify the caller's request.
+		req = req.Clone(req.Context())
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		t.logger.Debug("Serving revalidated response from cache", "url", entry.URL)
+		resp.Body.Close()
+		return entry.response(req, resp.Header), nil
+	}
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.store(path, &cacheEntry{
+		URL:          req.URL.String(),
+		ETag:         etag,
+		LastModified: lastModified,
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header,
+		Body:         body,
+	})
+	return resp, nil
+}
+
+// response rebuilds the cached response for req. Headers of the 304 response, such as
+// the current rate limit, take precedence over the cached ones.
+func (e *cacheEntry) response(req *http.Request, fresh http.Header) *http.Response {
+	header := e.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	for key, values := range fresh {
+		header[key] = values
+	}
+	header.Set(cacheStatusHeader, "revalidated")
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// entryPath returns the cache file for a request, keyed by URL, Accept header and credential.
+// The cache sits below the authenticating transports, so the Authorization header is already set
+// and is part of the key: a response fetched with one token, which may include private data, is
+// never served for another. Only a hash of the header ends up on disk. Rotating GitHub App
+// installation tokens start a fresh set of entries each time the token changes.
+func (t *cacheTransport) entryPath(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.URL.String() + "\n" + req.Header.Get("Accept") + "\n" + req.Header.Get("Authorization")))
+	return filepath.Join(t.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load reads a cache entry. Missing or unreadable entries are treated as cache misses.
+func (t *cacheTransport) load(path string) *cacheEntry {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			t.logger.Warn("Failed to read cache entry", "path", path, "error", err)
+		}
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.logger.Warn("Ignoring corrupt cache entry", "path", path, "error", err)
+		return nil
+	}
+	return &entry
+}
+
+// store writes a cache entry. Failures are logged and otherwise ignored, since the cache is only an optimisation.
+func (t *cacheTransport) store(path string, entry *cacheEntry) {
+	if err := os.MkdirAll(t.dir, 0755); err != nil {
+		t.logger.Warn("Failed to create cache directory", "path", t.dir, "error", err)
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		t.logger.Warn("Failed to encode cache entry", "url", entry.URL, "error", err)
+		return
+	}
+
+	// Write to a temporary file first so that a concurrent reader never sees a partial entry.
+	tmp, err := os.CreateTemp(t.dir, ".entry-*")
+	if err != nil {
+		t.logger.Warn("Failed to create cache entry", "path", path, "error", err)
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		t.logger.Warn("Failed to write cache entry", "path", path, "error", err)
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		t.logger.Warn("Failed to write cache entry", "path", path, "error", err)
+		return
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		t.logger.Warn("Failed to store cache entry", "path", path, "error", err)
+	}
+}
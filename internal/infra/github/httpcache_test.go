@@ -0,0 +1,115 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheTransport_ConditionalRequests(t *testing.T) {
+	t.Parallel()
+	repoName := "owner/repo"
+
+	var full, notModified atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc(fmt.Sprintf("/api/v3/repos/%s", repoName), func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"stargazers_count": 99}`)
+	})
+	server, transport := newTestServer(t, mux)
+
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+	cacheDir := t.TempDir()
+	newCachedClient := func() *Client {
+		ghClient := newTestGitHubClient(t, server, newCacheTransport(transport, cacheDir, logger))
+		return &Client{client: ghClient, logger: logger}
+	}
+
+	// The first run populates the cache; a later run, even in a new process, revalidates it.
+	for _, client := range []*Client{newCachedClient(), newCachedClient(), newCachedClient()} {
+		repo, err := client.FetchStars(context.Background(), repoName)
+		require.NoError(t, err)
+		assert.Equal(t, 99, repo.Stars)
+	}
+
+	assert.Equal(t, int32(1), full.Load())
+	assert.Equal(t, int32(2), notModified.Load())
+}
+
+func TestCacheTransport_SkipsNonCacheableResponses(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+	server, transport := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		require.Empty(t, r.Header.Get("If-None-Match"))
+		if r.Method == http.MethodGet {
+			// GET responses without validators must not be cached.
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+	}))
+
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+	client := &http.Client{Transport: newCacheTransport(transport, t.TempDir(), logger)}
+
+	for range 2 {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		resp, err = client.Post(server.URL, "application/json", nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestCacheTransport_SeparatesCredentials(t *testing.T) {
+	t.Parallel()
+
+	var full atomic.Int32
+	server, transport := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, r.Header.Get("Authorization"))
+	}))
+
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+	client := &http.Client{Transport: newCacheTransport(transport, t.TempDir(), logger)}
+
+	// A response cached for one token must not be served to another.
+	for _, auth := range []string{"token one", "token two", "token one"} {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("Authorization", auth)
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		require.NoError(t, err)
+		assert.Equal(t, auth, string(body))
+	}
+	assert.Equal(t, int32(2), full.Load())
+}
@@ -0,0 +1,108 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v79/github"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+// Lister is implemented by fetchers that can list the repositories of an owner.
+type Lister interface {
+	// ListOwnerRepos lists the public repositories of a GitHub organization or user.
+	ListOwnerRepos(ctx context.Context, owner domain.Owner) ([]domain.OwnedRepo, error)
+}
+
+// ListOwnerRepos implements Lister using the REST API.
+func (c *Client) ListOwnerRepos(ctx context.Context, owner domain.Owner) ([]domain.OwnedRepo, error) {
+	c.logger.Debug("Listing owner repositories", "owner", owner)
+
+	var repos []domain.OwnedRepo
+	page := 0
+	for {
+		ghRepos, resp, err := c.listOwnerPage(ctx, owner, page)
+		if err != nil {
+			c.logger.Error("Failed to list owner repositories", "owner", owner, "error", err)
+			return nil, fmt.Errorf("failed to list repositories of '%s': %w", owner, err)
+		}
+		for _, r := range ghRepos {
+			repos = append(repos, domain.OwnedRepo{
+				FullName: r.GetFullName(),
+				Stars:    r.GetStargazersCount(),
+				Language: r.GetLanguage(),
+				Fork:     r.GetFork(),
+				Archived: r.GetArchived(),
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		page = resp.NextPage
+	}
+
+	c.logger.Debug("Listed owner repositories", "owner", owner, "count", len(repos))
+	return repos, nil
+}
+
+// listOwnerPage fetches a page of an owner's public repositories, throttling and
+// retrying after rate limits like getRepository.
+func (c *Client) listOwnerPage(ctx context.Context, owner domain.Owner, page int) ([]*github.Repository, *github.Response, error) {
+	listOpts := github.ListOptions{Page: page, PerPage: 100}
+	for attempt := 0; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, nil, err
+		}
+
+		var (
+			repos []*github.Repository
+			resp  *github.Response
+			err   error
+		)
+		switch owner.Kind {
+		case domain.OwnerOrg:
+			repos, resp, err = c.client.Repositories.ListByOrg(ctx, owner.Name, &github.RepositoryListByOrgOptions{Type: "public", ListOptions: listOpts})
+		case domain.OwnerUser:
+			repos, resp, err = c.client.Repositories.ListByUser(ctx, owner.Name, &github.RepositoryListByUserOptions{Type: "owner", ListOptions: listOpts})
+		default:
+			return nil, nil, fmt.Errorf("unknown owner kind: %s", owner.Kind)
+		}
+		if resp != nil {
+			c.limiter.Observe(resp.Rate)
+		}
+		if err == nil || attempt >= maxRateLimitRetries || !c.limiter.Backoff(ctx, err) {
+			return repos, resp, err
+		}
+	}
+}
+
+// ListOwnerRepos implements Lister by delegating to the REST fallback,
+// since listing an owner's repositories is a single paginated request either way.
+func (c *GraphQLClient) ListOwnerRepos(ctx context.Context, owner domain.Owner) ([]domain.OwnedRepo, error) {
+	lister, ok := c.fallback.(Lister)
+	if !ok {
+		return nil, fmt.Errorf("listing repositories of '%s' is not supported", owner)
+	}
+	return lister.ListOwnerRepos(ctx, owner)
+}
+
+// ListOwnerRepos implements Lister if the wrapped fetcher does, retrying transient errors according to the policy.
+func (f *RetryFetcher) ListOwnerRepos(ctx context.Context, owner domain.Owner) ([]domain.OwnedRepo, error) {
+	lister, ok := f.next.(Lister)
+	if !ok {
+		return nil, fmt.Errorf("listing repositories of '%s' is not supported", owner)
+	}
+	for attempt := 1; ; attempt++ {
+		repos, err := lister.ListOwnerRepos(ctx, owner)
+		if err == nil || attempt >= f.policy.MaxAttempts || !IsRetryable(err) {
+			return repos, err
+		}
+
+		delay := f.policy.Backoff(attempt)
+		f.logger.Warn("Transient error while listing owner repositories, retrying", "owner", owner, "attempt", attempt, "delay", delay, "error", err)
+		if err := f.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
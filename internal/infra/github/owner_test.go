@@ -0,0 +1,55 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+func TestClient_ListOwnerRepos(t *testing.T) {
+	client, mux := setupTestClient(t, nil)
+	mux.HandleFunc("/api/v3/orgs/uber-go/repos", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "public", r.URL.Query().Get("type"))
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v3/orgs/uber-go/repos?page=2>; rel="next"`, r.Host))
+			fmt.Fprint(w, `[{"full_name": "uber-go/zap", "stargazers_count": 20000, "language": "Go"}]`)
+			return
+		}
+		fmt.Fprint(w, `[{"full_name": "uber-go/old", "stargazers_count": 5, "fork": true, "archived": true}]`)
+	})
+	mux.HandleFunc("/api/v3/users/spf13/repos", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "owner", r.URL.Query().Get("type"))
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"full_name": "spf13/cobra", "stargazers_count": 30000, "language": "Go"}]`)
+	})
+
+	repos, err := client.ListOwnerRepos(context.Background(), domain.Owner{Kind: domain.OwnerOrg, Name: "uber-go"})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.OwnedRepo{
+		{FullName: "uber-go/zap", Stars: 20000, Language: "Go"},
+		{FullName: "uber-go/old", Stars: 5, Fork: true, Archived: true},
+	}, repos)
+
+	repos, err = client.ListOwnerRepos(context.Background(), domain.Owner{Kind: domain.OwnerUser, Name: "spf13"})
+	require.NoError(t, err)
+	assert.Equal(t, []domain.OwnedRepo{{FullName: "spf13/cobra", Stars: 30000, Language: "Go"}}, repos)
+}
+
+func TestClient_ListOwnerRepos_NotFound(t *testing.T) {
+	client, mux := setupTestClient(t, nil)
+	mux.HandleFunc("/api/v3/orgs/missing/repos", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message": "Not Found"}`)
+	})
+
+	_, err := client.ListOwnerRepos(context.Background(), domain.Owner{Kind: domain.OwnerOrg, Name: "missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "org:missing")
+}
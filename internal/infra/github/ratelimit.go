@@ -0,0 +1,177 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v79/github"
+
+	"github.com/yourname/go-trendboard/internal/config"
+)
+
+const (
+	// defaultSecondaryRetryAfter is used when a secondary rate limit response carries no Retry-After.
+	defaultSecondaryRetryAfter = time.Minute
+	// maxRateLimitRetries bounds how many times a single request is retried after a rate limit.
+	maxRateLimitRetries = 3
+)
+
+// RateLimiter tracks the remaining GitHub API quota reported by responses and
+// schedules requests so that the quota is spread over the remaining window.
+// Its methods are safe for concurrent use, and a nil *RateLimiter never throttles.
+type RateLimiter struct {
+	mu        sync.Mutex
+	remaining int
+	reset     time.Time
+	next      time.Time
+
+	threshold int
+	wait      bool
+	maxWait   time.Duration
+
+	now    func() time.Time
+	sleep  func(ctx context.Context, d time.Duration) error
+	logger *slog.Logger
+}
+
+// NewRateLimiter creates a new RateLimiter from the rate limit settings in the configuration.
+func NewRateLimiter(cfg *config.Config, logger *slog.Logger) *RateLimiter {
+	return &RateLimiter{
+		remaining: -1, // Unknown until the first response is observed.
+		threshold: cfg.RateLimitThreshold,
+		wait:      cfg.RateLimitWait,
+		maxWait:   cfg.RateLimitMaxWait,
+		now:       time.Now,
+		sleep:     sleepContext,
+		logger:    logger.With("component", "rate_limiter"),
+	}
+}
+
+// Observe records the rate limit state reported by a response.
+func (l *RateLimiter) Observe(rate github.Rate) {
+	if l == nil || rate.Reset.IsZero() {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.remaining = rate.Remaining
+	l.reset = rate.Reset.Time
+}
+
+// Wait blocks until the next request may be sent.
+// Once the remaining quota drops below the threshold, requests are spaced evenly
+// until the reset time. When the quota is exhausted, Wait sleeps until the reset
+// only if waiting is enabled and the reset is within the maximum wait.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	delay := l.reserve()
+	if delay <= 0 {
+		return nil
+	}
+	l.logger.Debug("Throttling request to preserve rate limit", "delay", delay)
+	return l.sleep(ctx, delay)
+}
+
+// reserve computes how long the caller has to wait before its request and books the next slot.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	untilReset := l.reset.Sub(now)
+	if l.remaining < 0 || l.remaining >= l.threshold || untilReset <= 0 {
+		return 0
+	}
+
+	if l.remaining == 0 {
+		if !l.wait || untilReset > l.maxWait {
+			return 0 // Let the request fail fast with a rate limit error.
+		}
+		l.logger.Warn("Rate limit exhausted, waiting for reset", "reset", l.reset)
+		l.next = l.reset
+		return untilReset
+	}
+
+	interval := untilReset / time.Duration(l.remaining+1)
+	if interval > l.maxWait {
+		interval = l.maxWait
+	}
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(interval)
+	l.remaining--
+	return slot.Sub(now)
+}
+
+// Backoff decides whether a request that failed with err should be retried after a rate limit.
+// It sleeps until the request may be retried and reports whether the caller should retry.
+// Primary rate limits are only waited out when waiting is enabled; secondary rate limits
+// honour Retry-After. Either way the wait is bounded by the maximum wait.
+func (l *RateLimiter) Backoff(ctx context.Context, err error) bool {
+	if l == nil {
+		return false
+	}
+
+	var delay time.Duration
+	var rateErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	var respErr *github.ErrorResponse
+	switch {
+	case errors.As(err, &rateErr):
+		l.Observe(rateErr.Rate)
+		if !l.wait {
+			return false
+		}
+		delay = rateErr.Rate.Reset.Sub(l.now())
+	case errors.As(err, &abuseErr):
+		delay = abuseErr.GetRetryAfter()
+		if abuseErr.RetryAfter == nil {
+			delay = defaultSecondaryRetryAfter
+		}
+	case errors.As(err, &respErr) && respErr.Response != nil && respErr.Response.StatusCode == http.StatusTooManyRequests:
+		delay = retryAfter(respErr.Response.Header)
+	default:
+		return false
+	}
+
+	if delay > l.maxWait {
+		l.logger.Warn("Rate limit wait exceeds maximum, giving up", "wait", delay, "max_wait", l.maxWait)
+		return false
+	}
+	l.logger.Warn("Rate limited, waiting before retrying", "wait", delay)
+	return l.sleep(ctx, delay) == nil
+}
+
+// retryAfter parses the Retry-After header, falling back to a default delay.
+func retryAfter(header http.Header) time.Duration {
+	if secs, err := strconv.Atoi(header.Get("Retry-After")); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return defaultSecondaryRetryAfter
+}
+
+// sleepContext sleeps for d or until ctx is done, whichever happens first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,131 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v79/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourname/go-trendboard/internal/config"
+)
+
+// newTestRateLimiter creates a RateLimiter with a fixed clock that records sleeps instead of sleeping.
+func newTestRateLimiter(cfg *config.Config, now time.Time) (*RateLimiter, *[]time.Duration) {
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+	limiter := NewRateLimiter(cfg, logger)
+	var slept []time.Duration
+	limiter.now = func() time.Time { return now }
+	limiter.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	return limiter, &slept
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 11, 22, 12, 0, 0, 0, time.UTC)
+
+	t.Run("No throttling above threshold", func(t *testing.T) {
+		t.Parallel()
+		limiter, slept := newTestRateLimiter(&config.Config{RateLimitThreshold: 100, RateLimitMaxWait: time.Hour}, now)
+		limiter.Observe(github.Rate{Remaining: 500, Reset: github.Timestamp{Time: now.Add(time.Hour)}})
+
+		require.NoError(t, limiter.Wait(context.Background()))
+		assert.Empty(t, *slept)
+	})
+
+	t.Run("Spreads requests below threshold", func(t *testing.T) {
+		t.Parallel()
+		limiter, slept := newTestRateLimiter(&config.Config{RateLimitThreshold: 100, RateLimitMaxWait: time.Hour}, now)
+		limiter.Observe(github.Rate{Remaining: 9, Reset: github.Timestamp{Time: now.Add(10 * time.Minute)}})
+
+		require.NoError(t, limiter.Wait(context.Background()))
+		require.NoError(t, limiter.Wait(context.Background()))
+		// The first request goes out immediately, the second one a full interval later.
+		assert.Equal(t, []time.Duration{time.Minute}, *slept)
+	})
+
+	t.Run("Waits for reset when exhausted", func(t *testing.T) {
+		t.Parallel()
+		limiter, slept := newTestRateLimiter(&config.Config{RateLimitThreshold: 100, RateLimitWait: true, RateLimitMaxWait: time.Hour}, now)
+		limiter.Observe(github.Rate{Remaining: 0, Reset: github.Timestamp{Time: now.Add(5 * time.Minute)}})
+
+		require.NoError(t, limiter.Wait(context.Background()))
+		assert.Equal(t, []time.Duration{5 * time.Minute}, *slept)
+	})
+
+	t.Run("Does not wait beyond max wait", func(t *testing.T) {
+		t.Parallel()
+		limiter, slept := newTestRateLimiter(&config.Config{RateLimitThreshold: 100, RateLimitWait: true, RateLimitMaxWait: time.Minute}, now)
+		limiter.Observe(github.Rate{Remaining: 0, Reset: github.Timestamp{Time: now.Add(30 * time.Minute)}})
+
+		require.NoError(t, limiter.Wait(context.Background()))
+		assert.Empty(t, *slept)
+	})
+}
+
+func TestClient_FetchStars_RateLimitRetry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Primary rate limit is waited out", func(t *testing.T) {
+		t.Parallel()
+		client, mux := setupTestClient(t, nil)
+		client.limiter, _ = newTestRateLimiter(&config.Config{RateLimitWait: true, RateLimitMaxWait: time.Hour}, time.Now())
+		repoName := "owner/repo"
+
+		var calls atomic.Int32
+		mux.HandleFunc(fmt.Sprintf("/api/v3/repos/%s", repoName), func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				// A reset time that has already passed lets go-github send the retry.
+				w.Header().Set("X-RateLimit-Limit", "5000")
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10))
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"message": "API rate limit exceeded"}`)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"stargazers_count": 7}`)
+		})
+
+		repo, err := client.FetchStars(context.Background(), repoName)
+		require.NoError(t, err)
+		assert.Equal(t, 7, repo.Stars)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Secondary rate limit honours Retry-After", func(t *testing.T) {
+		t.Parallel()
+		client, mux := setupTestClient(t, nil)
+		var slept *[]time.Duration
+		client.limiter, slept = newTestRateLimiter(&config.Config{RateLimitMaxWait: time.Hour}, time.Now())
+		repoName := "owner/repo"
+
+		var calls atomic.Int32
+		mux.HandleFunc(fmt.Sprintf("/api/v3/repos/%s", repoName), func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"message": "You have exceeded a secondary rate limit", "documentation_url": "https://docs.github.com/rest/overview/rate-limits-for-the-rest-api#about-secondary-rate-limits"}`)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"stargazers_count": 7}`)
+		})
+
+		repo, err := client.FetchStars(context.Background(), repoName)
+		require.NoError(t, err)
+		assert.Equal(t, 7, repo.Stars)
+		assert.Equal(t, []time.Duration{0}, *slept)
+	})
+}
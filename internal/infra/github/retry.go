@@ -0,0 +1,160 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/google/go-github/v79/github"
+
+	"github.com/yourname/go-trendboard/internal/config"
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+// RetryPolicy controls how fetches that failed with a transient error are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. It doubles on every further retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff time.Duration
+	// Jitter is the fraction (0 to 1) of each delay that is randomised to avoid retry storms.
+	Jitter float64
+}
+
+// NewRetryPolicy creates a RetryPolicy from the retry settings in the configuration.
+func NewRetryPolicy(cfg *config.Config) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    cfg.RetryMaxAttempts,
+		InitialBackoff: cfg.RetryInitialBackoff,
+		MaxBackoff:     cfg.RetryMaxBackoff,
+		Jitter:         cfg.RetryJitter,
+	}
+}
+
+// Backoff returns the delay before the given retry (1 for the first retry).
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < retry && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if jitter := min(max(p.Jitter, 0), 1); jitter > 0 {
+		d -= time.Duration(jitter * rand.Float64() * float64(d))
+	}
+	return d
+}
+
+// IsRetryable reports whether err is a transient error worth retrying:
+// server errors, request timeouts and network failures.
+// Rate limits are not retried here; they are handled by the RateLimiter.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return false
+	}
+
+	var respErr *github.ErrorResponse
+	if errors.As(err, &respErr) && respErr.Response != nil {
+		code := respErr.Response.StatusCode
+		return code >= http.StatusInternalServerError || code == http.StatusRequestTimeout
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// RetryFetcher is a Fetcher decorator that retries transient fetch errors with exponential backoff.
+type RetryFetcher struct {
+	next   Fetcher
+	policy RetryPolicy
+	sleep  func(ctx context.Context, d time.Duration) error
+	logger *slog.Logger
+}
+
+// retryBatchFetcher is a RetryFetcher that wraps a BatchFetcher, preserving batched fetching.
+type retryBatchFetcher struct {
+	*RetryFetcher
+	next BatchFetcher
+}
+
+// NewRetryFetcher wraps next with the given retry policy.
+// If next is a BatchFetcher, the returned Fetcher is one as well.
+func NewRetryFetcher(next Fetcher, policy RetryPolicy, logger *slog.Logger) Fetcher {
+	rf := &RetryFetcher{
+		next:   next,
+		policy: policy,
+		sleep:  sleepContext,
+		logger: logger.With("component", "retry_fetcher"),
+	}
+	if bf, ok := next.(BatchFetcher); ok {
+		return &retryBatchFetcher{RetryFetcher: rf, next: bf}
+	}
+	return rf
+}
+
+// FetchStars fetches a repository, retrying transient errors according to the policy.
+func (f *RetryFetcher) FetchStars(ctx context.Context, repoName string) (*domain.Repository, error) {
+	for attempt := 1; ; attempt++ {
+		repo, err := f.next.FetchStars(ctx, repoName)
+		if err == nil || attempt >= f.policy.MaxAttempts || !IsRetryable(err) {
+			return repo, err
+		}
+
+		delay := f.policy.Backoff(attempt)
+		f.logger.Warn("Transient error while fetching repository, retrying", "repo", repoName, "attempt", attempt, "delay", delay, "error", err)
+		if err := f.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// FetchStarsBatch fetches the repositories as a batch, then re-fetches the ones
+// that failed with a transient error as smaller batches until the policy gives up.
+func (f *retryBatchFetcher) FetchStarsBatch(ctx context.Context, repoNames []string) []FetchResult {
+	results := f.next.FetchStarsBatch(ctx, repoNames)
+
+	for attempt := 1; attempt < f.policy.MaxAttempts; attempt++ {
+		var pending []int
+		for i, result := range results {
+			if IsRetryable(result.Err) {
+				pending = append(pending, i)
+			}
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		delay := f.policy.Backoff(attempt)
+		f.logger.Warn("Transient errors while fetching repositories, retrying", "count", len(pending), "attempt", attempt, "delay", delay)
+		if err := f.sleep(ctx, delay); err != nil {
+			break
+		}
+
+		retryNames := make([]string, len(pending))
+		for j, i := range pending {
+			retryNames[j] = repoNames[i]
+		}
+		for j, result := range f.next.FetchStarsBatch(ctx, retryNames) {
+			results[pending[j]] = result
+		}
+	}
+	return results
+}
@@ -0,0 +1,172 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v79/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+// flakyFetcher fails each repository with the given error a number of times before succeeding.
+type flakyFetcher struct {
+	mu       sync.Mutex
+	failures map[string]int
+	err      error
+	calls    map[string]int
+	batches  int
+}
+
+func newFlakyFetcher(err error, failures map[string]int) *flakyFetcher {
+	return &flakyFetcher{failures: failures, err: err, calls: map[string]int{}}
+}
+
+func (f *flakyFetcher) FetchStars(ctx context.Context, repoName string) (*domain.Repository, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls[repoName]++
+	if f.calls[repoName] <= f.failures[repoName] {
+		return nil, &FetchError{repoName: repoName, cause: f.err}
+	}
+	return domain.NewRepository(repoName, 10)
+}
+
+// flakyBatchFetcher is a flakyFetcher that also implements BatchFetcher.
+type flakyBatchFetcher struct {
+	*flakyFetcher
+}
+
+func (f flakyBatchFetcher) FetchStarsBatch(ctx context.Context, repoNames []string) []FetchResult {
+	f.mu.Lock()
+	f.batches++
+	f.mu.Unlock()
+	results := make([]FetchResult, len(repoNames))
+	for i, repoName := range repoNames {
+		repo, err := f.FetchStars(ctx, repoName)
+		results[i] = FetchResult{RepoName: repoName, Repository: repo, Err: err}
+	}
+	return results
+}
+
+// serverError builds a go-github error response with the given status code.
+func serverError(code int) error {
+	return &github.ErrorResponse{Response: &http.Response{StatusCode: code, Request: &http.Request{}}}
+}
+
+func newTestRetryFetcher(next Fetcher, maxAttempts int) Fetcher {
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+	f := NewRetryFetcher(next, RetryPolicy{MaxAttempts: maxAttempts, InitialBackoff: time.Millisecond}, logger)
+	noSleep := func(ctx context.Context, d time.Duration) error { return nil }
+	switch rf := f.(type) {
+	case *RetryFetcher:
+		rf.sleep = noSleep
+	case *retryBatchFetcher:
+		rf.sleep = noSleep
+	}
+	return f
+}
+
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "Nil", err: nil, expected: false},
+		{name: "Server error", err: &FetchError{cause: serverError(http.StatusBadGateway)}, expected: true},
+		{name: "Request timeout", err: serverError(http.StatusRequestTimeout), expected: true},
+		{name: "Not found", err: &FetchError{cause: serverError(http.StatusNotFound)}, expected: false},
+		{name: "Rate limited", err: &RateLimitError{cause: errors.New("limited")}, expected: false},
+		{name: "Context canceled", err: fmt.Errorf("wrapped: %w", context.Canceled), expected: false},
+		{name: "Deadline exceeded", err: context.DeadlineExceeded, expected: true},
+		{name: "Other error", err: errors.New("invalid repository full name format"), expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, IsRetryable(tc.err))
+		})
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	t.Parallel()
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	assert.Equal(t, time.Second, policy.Backoff(1))
+	assert.Equal(t, 2*time.Second, policy.Backoff(2))
+	assert.Equal(t, 4*time.Second, policy.Backoff(3))
+	assert.Equal(t, 5*time.Second, policy.Backoff(4))
+
+	policy.Jitter = 0.5
+	for retry := 1; retry <= 4; retry++ {
+		d := policy.Backoff(retry)
+		assert.LessOrEqual(t, d, 5*time.Second)
+		assert.Greater(t, d, time.Duration(0))
+	}
+}
+
+func TestRetryFetcher_FetchStars(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Retries transient errors", func(t *testing.T) {
+		t.Parallel()
+		next := newFlakyFetcher(serverError(http.StatusServiceUnavailable), map[string]int{"owner/repo": 2})
+		fetcher := newTestRetryFetcher(next, 3)
+
+		repo, err := fetcher.FetchStars(context.Background(), "owner/repo")
+		require.NoError(t, err)
+		assert.Equal(t, 10, repo.Stars)
+		assert.Equal(t, 3, next.calls["owner/repo"])
+	})
+
+	t.Run("Gives up after max attempts", func(t *testing.T) {
+		t.Parallel()
+		next := newFlakyFetcher(serverError(http.StatusServiceUnavailable), map[string]int{"owner/repo": 5})
+		fetcher := newTestRetryFetcher(next, 3)
+
+		_, err := fetcher.FetchStars(context.Background(), "owner/repo")
+		require.Error(t, err)
+		assert.Equal(t, 3, next.calls["owner/repo"])
+	})
+
+	t.Run("Does not retry permanent errors", func(t *testing.T) {
+		t.Parallel()
+		next := newFlakyFetcher(serverError(http.StatusNotFound), map[string]int{"owner/repo": 5})
+		fetcher := newTestRetryFetcher(next, 3)
+
+		_, err := fetcher.FetchStars(context.Background(), "owner/repo")
+		require.Error(t, err)
+		assert.Equal(t, 1, next.calls["owner/repo"])
+	})
+}
+
+func TestRetryFetcher_FetchStarsBatch(t *testing.T) {
+	t.Parallel()
+	next := flakyBatchFetcher{newFlakyFetcher(serverError(http.StatusBadGateway), map[string]int{"owner/flaky": 1})}
+	fetcher := newTestRetryFetcher(next, 3)
+
+	bf, ok := fetcher.(BatchFetcher)
+	require.True(t, ok, "wrapping a BatchFetcher should preserve batching")
+
+	results := bf.FetchStarsBatch(context.Background(), []string{"owner/stable", "owner/flaky"})
+	for _, result := range results {
+		require.NoError(t, result.Err)
+	}
+	assert.Equal(t, 2, next.batches)
+	assert.Equal(t, 1, next.calls["owner/stable"])
+	assert.Equal(t, 2, next.calls["owner/flaky"])
+}
@@ -0,0 +1,69 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v79/github"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+// maxSearchPageSize is the largest page size the search API accepts.
+const maxSearchPageSize = 100
+
+// Searcher is implemented by clients that can search GitHub for repositories.
+type Searcher interface {
+	// SearchRepos returns at most limit repositories matching a GitHub search query,
+	// such as "language:Go stars:>500", ordered by star count.
+	SearchRepos(ctx context.Context, query string, limit int) ([]*domain.Repository, error)
+}
+
+// SearchRepos implements Searcher using the repository search API.
+func (c *Client) SearchRepos(ctx context.Context, query string, limit int) ([]*domain.Repository, error) {
+	c.logger.Debug("Searching repositories", "query", query, "limit", limit)
+
+	opts := &github.SearchOptions{
+		Sort:        "stars",
+		Order:       "desc",
+		ListOptions: github.ListOptions{PerPage: min(limit, maxSearchPageSize)},
+	}
+	var repos []*domain.Repository
+	for len(repos) < limit {
+		result, resp, err := c.searchRepositories(ctx, query, opts)
+		if err != nil {
+			c.logger.Error("Failed to search repositories", "query", query, "error", err)
+			return nil, fmt.Errorf("failed to search repositories for '%s': %w", query, err)
+		}
+		for _, ghRepo := range result.Repositories {
+			if len(repos) == limit {
+				break
+			}
+			repo, err := domain.NewRepository(ghRepo.GetFullName(), ghRepo.GetStargazersCount())
+			if err != nil {
+				c.logger.Warn("Skipping search result with an invalid name", "repo", ghRepo.GetFullName(), "error", err)
+				continue
+			}
+			repos = append(repos, repo)
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	c.logger.Debug("Search finished", "query", query, "count", len(repos))
+	return repos, nil
+}
+
+// searchRepositories fetches a page of search results, retrying after rate limits as permitted by the rate limiter.
+// Successful responses aren't observed by the limiter: the search API has a separate, much smaller
+// quota that would otherwise throttle the core requests.
+func (c *Client) searchRepositories(ctx context.Context, query string, opts *github.SearchOptions) (*github.RepositoriesSearchResult, *github.Response, error) {
+	for attempt := 0; ; attempt++ {
+		result, resp, err := c.client.Search.Repositories(ctx, query, opts)
+		if err == nil || attempt >= maxRateLimitRetries || !c.limiter.Backoff(ctx, err) {
+			return result, resp, err
+		}
+	}
+}
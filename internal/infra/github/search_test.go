@@ -0,0 +1,51 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_SearchRepos(t *testing.T) {
+	var pages []string
+	client, mux := setupTestClient(t, nil)
+	mux.HandleFunc("/api/v3/search/repositories", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "language:Go stars:>500", r.URL.Query().Get("q"))
+		assert.Equal(t, "stars", r.URL.Query().Get("sort"))
+		assert.Equal(t, "3", r.URL.Query().Get("per_page"))
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+
+		w.Header().Set("Content-Type", "application/json")
+		if page == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v3/search/repositories?page=2>; rel="next"`, r.Host))
+			fmt.Fprint(w, `{"items": [{"full_name": "owner/a", "stargazers_count": 900}, {"full_name": "owner/b", "stargazers_count": 800}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"items": [{"full_name": "owner/c", "stargazers_count": 700}, {"full_name": "owner/d", "stargazers_count": 600}]}`)
+	})
+
+	repos, err := client.SearchRepos(context.Background(), "language:Go stars:>500", 3)
+	require.NoError(t, err)
+	require.Len(t, repos, 3)
+	assert.Equal(t, "owner/a", repos[0].FullName)
+	assert.Equal(t, 900, repos[0].Stars)
+	assert.Equal(t, "owner/c", repos[2].FullName)
+	assert.Equal(t, []string{"", "2"}, pages)
+}
+
+func TestClient_SearchRepos_Error(t *testing.T) {
+	client, mux := setupTestClient(t, nil)
+	mux.HandleFunc("/api/v3/search/repositories", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"message": "Validation Failed"}`)
+	})
+
+	_, err := client.SearchRepos(context.Background(), "stars:>>", 10)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stars:>>")
+}
@@ -0,0 +1,249 @@
+package github
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// TokenStrategyRoundRobin uses the tokens of a pool in turn.
+	TokenStrategyRoundRobin = "round-robin"
+	// TokenStrategyMostRemaining uses the token of a pool with the most remaining quota.
+	TokenStrategyMostRemaining = "most-remaining"
+)
+
+// tokenQuota is the rate limit state last reported for a token and one API resource.
+type tokenQuota struct {
+	limit     int
+	remaining int // -1 until a response for the token has been seen.
+	reset     time.Time
+}
+
+// pooledToken is a token of a pool along with its rate limit state per API resource,
+// since the REST, search and GraphQL APIs each have a quota of their own.
+type pooledToken struct {
+	value  string
+	quotas map[string]*tokenQuota
+}
+
+// quota returns the rate limit state of the token for resource.
+func (t *pooledToken) quota(resource string) tokenQuota {
+	if q, ok := t.quotas[resource]; ok {
+		return *q
+	}
+	return tokenQuota{remaining: -1}
+}
+
+// exhausted reports whether the token has no quota left for resource until its reset time.
+func (t *pooledToken) exhausted(resource string, now time.Time) bool {
+	q := t.quota(resource)
+	return q.remaining == 0 && now.Before(q.reset)
+}
+
+// tokenPoolTransport is an http.RoundTripper that spreads requests across several tokens.
+// A request rejected by the primary rate limit is transparently retried with another
+// token, and exhausted tokens are skipped until their rate limit resets.
+// The rate limit headers of its responses report the combined quota of the pool rather
+// than that of the token that happened to serve the request, so that a RateLimiter
+// observing them paces requests by what the pool as a whole has left.
+type tokenPoolTransport struct {
+	base     http.RoundTripper
+	strategy string
+
+	mu     sync.Mutex
+	tokens []*pooledToken
+	next   int
+
+	now    func() time.Time
+	logger *slog.Logger
+}
+
+// newTokenPoolTransport creates a tokenPoolTransport for the given tokens and selection strategy.
+func newTokenPoolTransport(base http.RoundTripper, tokens []string, strategy string, logger *slog.Logger) (*tokenPoolTransport, error) {
+	switch strategy {
+	case "", TokenStrategyRoundRobin, TokenStrategyMostRemaining:
+	default:
+		return nil, fmt.Errorf("unknown token strategy: %s", strategy)
+	}
+
+	pool := &tokenPoolTransport{
+		base:     base,
+		strategy: strategy,
+		now:      time.Now,
+		logger:   logger.With("component", "token_pool"),
+	}
+	seen := make(map[string]bool, len(tokens))
+	for _, token := range tokens {
+		if token == "" || seen[token] {
+			continue
+		}
+		seen[token] = true
+		pool.tokens = append(pool.tokens, &pooledToken{value: token, quotas: map[string]*tokenQuota{}})
+	}
+	if len(pool.tokens) == 0 {
+		return nil, fmt.Errorf("token pool is empty")
+	}
+	return pool, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (p *tokenPoolTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resource := requestResource(req)
+	tried := make(map[*pooledToken]bool, len(p.tokens))
+	for {
+		token := p.pick(resource, tried)
+		tried[token] = true
+
+		attempt := req.Clone(req.Context())
+		attempt.Header.Set("Authorization", "Bearer "+token.value)
+		if len(tried) > 1 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attempt.Body = body
+		}
+
+		resp, err := p.base.RoundTrip(attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		limited := p.observe(token, resource, resp)
+		// Requests whose body can't be replayed are never retried.
+		canRetry := req.Body == nil || req.GetBody != nil
+		if !limited || !canRetry || len(tried) == len(p.tokens) {
+			p.report(resource, resp, limited)
+			return resp, nil
+		}
+
+		p.logger.Warn("Token exhausted its rate limit, switching to another token",
+			"token", p.index(token), "resource", resource, "reset", token.quota(resource).reset)
+		resp.Body.Close()
+	}
+}
+
+// pick selects the token for the next request to resource, skipping tokens already tried for it.
+// Exhausted tokens are only used when no other token is left, preferring the one that resets first.
+func (p *tokenPoolTransport) pick(resource string, tried map[*pooledToken]bool) *pooledToken {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	var best *pooledToken
+	for i := range p.tokens {
+		idx := (p.next + i) % len(p.tokens)
+		token := p.tokens[idx]
+		if tried[token] || token.exhausted(resource, now) {
+			continue
+		}
+		if p.strategy != TokenStrategyMostRemaining {
+			p.next = idx + 1
+			return token
+		}
+		remaining, bestRemaining := token.quota(resource).remaining, -1
+		if best != nil {
+			bestRemaining = best.quota(resource).remaining
+		}
+		if best == nil || remaining < 0 || (bestRemaining >= 0 && remaining > bestRemaining) {
+			best = token
+		}
+	}
+	if best != nil {
+		return best
+	}
+
+	for _, token := range p.tokens {
+		if !tried[token] && (best == nil || token.quota(resource).reset.Before(best.quota(resource).reset)) {
+			best = token
+		}
+	}
+	if best == nil {
+		// Every token has been tried; this only happens for pools of one.
+		best = p.tokens[0]
+	}
+	return best
+}
+
+// observe records the rate limit state reported for token and resource, and reports whether
+// the response was rejected because the token's primary rate limit is exhausted.
+func (p *tokenPoolTransport) observe(token *pooledToken, resource string, resp *http.Response) bool {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return false
+	}
+	limit, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+	reset, _ := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+
+	p.mu.Lock()
+	token.quotas[resource] = &tokenQuota{limit: limit, remaining: remaining, reset: time.Unix(reset, 0)}
+	p.mu.Unlock()
+
+	return remaining == 0 && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests)
+}
+
+// report replaces the rate limit headers of resp with the combined quota of the pool for resource.
+// Tokens not seen yet, or whose window has reset since, are assumed to have the limit reported
+// by resp. The reset time is the earliest one of the pool, when more quota becomes available.
+// A response that was rejected by the rate limit keeps reporting no remaining quota.
+func (p *tokenPoolTransport) report(resource string, resp *http.Response, limited bool) {
+	if resp.Header.Get("X-RateLimit-Remaining") == "" {
+		return
+	}
+	fresh, _ := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit"))
+
+	p.mu.Lock()
+	now := p.now()
+	var limit, remaining int
+	var reset time.Time
+	for _, token := range p.tokens {
+		q := token.quota(resource)
+		if q.remaining < 0 || !now.Before(q.reset) {
+			limit += fresh
+			remaining += fresh
+			continue
+		}
+		limit += q.limit
+		remaining += q.remaining
+		if reset.IsZero() || q.reset.Before(reset) {
+			reset = q.reset
+		}
+	}
+	p.mu.Unlock()
+
+	if limited {
+		remaining = 0
+	}
+	resp.Header.Set("X-RateLimit-Limit", strconv.Itoa(limit))
+	resp.Header.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	if !reset.IsZero() {
+		resp.Header.Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+	}
+}
+
+// requestResource returns the API resource whose quota a request counts against.
+func requestResource(req *http.Request) string {
+	switch path := req.URL.Path; {
+	case strings.HasSuffix(path, "/graphql"):
+		return "graphql"
+	case strings.Contains(path, "/search/"):
+		return "search"
+	default:
+		return "core"
+	}
+}
+
+// index returns the position of token in the pool, used to identify tokens in logs without leaking them.
+func (p *tokenPoolTransport) index(token *pooledToken) int {
+	for i, t := range p.tokens {
+		if t == token {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,133 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenServer is a test API server that tracks the quota of each token it sees.
+type tokenServer struct {
+	mu        sync.Mutex
+	remaining map[string]int
+	calls     map[string]int
+}
+
+func (s *tokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	s.mu.Lock()
+	s.calls[token]++
+	remaining := s.remaining[token]
+	if remaining > 0 {
+		s.remaining[token]--
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("X-RateLimit-Limit", "5000")
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+	if remaining == 0 {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message": "API rate limit exceeded"}`)
+		return
+	}
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining-1))
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprint(w, `{"stargazers_count": 1}`)
+}
+
+// setupTokenPoolClient creates a Client that rotates the given tokens against a tokenServer.
+func setupTokenPoolClient(t *testing.T, remaining map[string]int, tokens []string, strategy string) (*Client, *tokenServer) {
+	t.Helper()
+	ts := &tokenServer{remaining: remaining, calls: map[string]int{}}
+	server, transport := newTestServer(t, ts)
+
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+	pool, err := newTokenPoolTransport(transport, tokens, strategy, logger)
+	require.NoError(t, err)
+
+	return &Client{client: newTestGitHubClient(t, server, pool), logger: logger}, ts
+}
+
+func TestTokenPool_RoundRobin(t *testing.T) {
+	t.Parallel()
+	client, server := setupTokenPoolClient(t, map[string]int{"a": 100, "b": 100}, []string{"a", "b", "a"}, TokenStrategyRoundRobin)
+
+	for range 4 {
+		_, err := client.FetchStars(context.Background(), "owner/repo")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, map[string]int{"a": 2, "b": 2}, server.calls)
+}
+
+func TestTokenPool_SwitchesAwayFromExhaustedToken(t *testing.T) {
+	t.Parallel()
+	client, server := setupTokenPoolClient(t, map[string]int{"a": 0, "b": 100}, []string{"a", "b"}, TokenStrategyRoundRobin)
+
+	for range 3 {
+		repo, err := client.FetchStars(context.Background(), "owner/repo")
+		require.NoError(t, err)
+		assert.Equal(t, 1, repo.Stars)
+	}
+	// The exhausted token is tried once and then skipped until its reset.
+	assert.Equal(t, map[string]int{"a": 1, "b": 3}, server.calls)
+}
+
+func TestTokenPool_MostRemaining(t *testing.T) {
+	t.Parallel()
+	client, server := setupTokenPoolClient(t, map[string]int{"a": 5, "b": 50}, []string{"a", "b"}, TokenStrategyMostRemaining)
+
+	for range 5 {
+		_, err := client.FetchStars(context.Background(), "owner/repo")
+		require.NoError(t, err)
+	}
+	// Both tokens are probed once, after which the one with more quota is preferred.
+	assert.Equal(t, map[string]int{"a": 1, "b": 4}, server.calls)
+}
+
+func TestTokenPool_AllExhausted(t *testing.T) {
+	t.Parallel()
+	client, server := setupTokenPoolClient(t, map[string]int{"a": 0, "b": 0}, []string{"a", "b"}, TokenStrategyRoundRobin)
+
+	_, err := client.FetchStars(context.Background(), "owner/repo")
+	var rateLimitErr *RateLimitError
+	require.ErrorAs(t, err, &rateLimitErr)
+	assert.Equal(t, map[string]int{"a": 1, "b": 1}, server.calls)
+}
+
+func TestNewTokenPoolTransport_UnknownStrategy(t *testing.T) {
+	t.Parallel()
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+	_, err := newTokenPoolTransport(http.DefaultTransport, []string{"a"}, "random", logger)
+	require.Error(t, err)
+}
+
+func TestTokenPool_ReportsCombinedQuota(t *testing.T) {
+	t.Parallel()
+	ts := &tokenServer{remaining: map[string]int{"a": 10, "b": 100}, calls: map[string]int{}}
+	server, transport := newTestServer(t, ts)
+
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+	pool, err := newTokenPoolTransport(transport, []string{"a", "b"}, TokenStrategyRoundRobin, logger)
+	require.NoError(t, err)
+	client := &http.Client{Transport: pool}
+
+	// Until it has been used, the second token is assumed to have the full limit.
+	for _, want := range []string{"5009", "108"} {
+		resp, err := client.Get(server.URL + "/repos/owner/repo")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, want, resp.Header.Get("X-RateLimit-Remaining"))
+	}
+}
@@ -0,0 +1,88 @@
+package presenter
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+// Leaderboard is a ranked table of trends.
+type Leaderboard struct {
+	// Category is the tag the leaderboard is restricted to; it is empty for the overall leaderboard.
+	Category string
+	// Trends are the trends of the leaderboard, in rank order.
+	Trends []*domain.Trend
+}
+
+// Dashboard is the view model rendered by presenters:
+// an overall leaderboard followed by one leaderboard per category.
+type Dashboard struct {
+	// Period is the period over which the trends were calculated.
+	Period domain.TrendPeriod
+	// Overall ranks every repository, or those in the selected categories.
+	Overall Leaderboard
+	// Categories holds one leaderboard per category, in the order categories were selected,
+	// or alphabetically when every category is rendered.
+	Categories []Leaderboard
+}
+
+// NewDashboard groups trends, which must already be sorted, into leaderboards.
+// When categories is empty, a leaderboard is built for every tag carried by a repository.
+// Otherwise only the given categories are rendered and the overall leaderboard is
+// restricted to repositories in at least one of them. Tags are matched ignoring case.
+func NewDashboard(period domain.TrendPeriod, trends []*domain.Trend, categories []string) *Dashboard {
+	dashboard := &Dashboard{Period: period, Overall: Leaderboard{Trends: trends}}
+	if len(categories) == 0 {
+		categories = collectCategories(trends)
+	} else {
+		dashboard.Overall.Trends = nil
+		for _, trend := range trends {
+			if slices.ContainsFunc(categories, trendHasTag(trend)) {
+				dashboard.Overall.Trends = append(dashboard.Overall.Trends, trend)
+			}
+		}
+	}
+
+	for _, category := range categories {
+		board := Leaderboard{Category: category}
+		for _, trend := range dashboard.Overall.Trends {
+			if trendHasTag(trend)(category) {
+				board.Trends = append(board.Trends, trend)
+			}
+		}
+		if len(board.Trends) > 0 {
+			dashboard.Categories = append(dashboard.Categories, board)
+		}
+	}
+	return dashboard
+}
+
+// trendHasTag returns a function reporting whether the repository of trend carries a tag.
+func trendHasTag(trend *domain.Trend) func(tag string) bool {
+	return func(tag string) bool {
+		return trend.Target != nil && trend.Target.HasTag(tag)
+	}
+}
+
+// collectCategories returns every tag carried by the repositories of trends, sorted
+// alphabetically. Tags differing only in case are reported once, as first seen.
+func collectCategories(trends []*domain.Trend) []string {
+	seen := make(map[string]bool)
+	var categories []string
+	for _, trend := range trends {
+		if trend.Target == nil {
+			continue
+		}
+		for _, tag := range trend.Target.Tags {
+			if key := strings.ToLower(tag); !seen[key] {
+				seen[key] = true
+				categories = append(categories, tag)
+			}
+		}
+	}
+	slices.SortFunc(categories, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	return categories
+}
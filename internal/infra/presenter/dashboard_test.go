@@ -0,0 +1,43 @@
+package presenter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+func TestNewDashboard(t *testing.T) {
+	newTrend := func(name string, diff int, tags ...string) *domain.Trend {
+		repo, err := domain.NewRepository(name, 100)
+		require.NoError(t, err)
+		trend := domain.NewTrend(repo, diff, domain.TrendWeekly)
+		trend.Target = &domain.TargetRepo{Name: name, Tags: tags}
+		return trend
+	}
+	web := newTrend("owner/web", 30, "Web")
+	orm := newTrend("owner/orm", 20, "orm")
+	both := newTrend("owner/both", 10, "web", "orm")
+	untagged := newTrend("owner/untagged", 5)
+	unlisted, _ := domain.NewRepository("owner/unlisted", 1)
+	trends := []*domain.Trend{web, orm, both, untagged, domain.NewTrend(unlisted, 1, domain.TrendWeekly)}
+
+	t.Run("All categories", func(t *testing.T) {
+		dashboard := NewDashboard(domain.TrendWeekly, trends, nil)
+		assert.Equal(t, trends, dashboard.Overall.Trends)
+		assert.Equal(t, []Leaderboard{
+			{Category: "orm", Trends: []*domain.Trend{orm, both}},
+			{Category: "Web", Trends: []*domain.Trend{web, both}},
+		}, dashboard.Categories)
+	})
+
+	t.Run("Selected categories", func(t *testing.T) {
+		dashboard := NewDashboard(domain.TrendWeekly, trends, []string{"web", "cli"})
+		assert.Equal(t, []*domain.Trend{web, both}, dashboard.Overall.Trends)
+		assert.Equal(t, []Leaderboard{
+			{Category: "web", Trends: []*domain.Trend{web, both}},
+		}, dashboard.Categories)
+	})
+}
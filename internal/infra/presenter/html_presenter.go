@@ -7,18 +7,18 @@ import (
 	"log/slog"
 	"os"
 	"time"
-
-	"github.com/yourname/go-trendboard/internal/domain"
 )
 
 // HTMLPresenter renders trend data as an HTML page.
 type HTMLPresenter struct {
 	templatePath string
+	webURL       string
 	logger       *slog.Logger
 }
 
 // NewHTMLPresenter creates a new HTMLPresenter.
-func NewHTMLPresenter(templatePath string, logger *slog.Logger) (*HTMLPresenter, error) {
+// Repository links are built from webURL, the root URL of the GitHub web interface.
+func NewHTMLPresenter(templatePath, webURL string, logger *slog.Logger) (*HTMLPresenter, error) {
 	// A simple check to see if the template file is accessible.
 	// The actual parsing happens in Render.
 	if _, err := os.Stat(templatePath); os.IsNotExist(err) {
@@ -27,21 +27,32 @@ func NewHTMLPresenter(templatePath string, logger *slog.Logger) (*HTMLPresenter,
 	}
 	return &HTMLPresenter{
 		templatePath: templatePath,
+		webURL:       webURL,
 		logger:       logger.With("component", "html_presenter"),
 	}, nil
 }
 
-// Render generates an HTML report from the trend data.
-func (p *HTMLPresenter) Render(writer io.Writer, trends []*domain.Trend) error {
-	p.logger.Debug("Rendering trends to HTML", "template", p.templatePath)
-
+// parseTemplate parses the HTML template.
+func (p *HTMLPresenter) parseTemplate() (*template.Template, error) {
 	tmpl, err := template.ParseFiles(p.templatePath)
 	if err != nil {
 		p.logger.Error("Failed to parse HTML template", "error", err)
-		return fmt.Errorf("failed to parse HTML template: %w", err)
+		return nil, fmt.Errorf("failed to parse HTML template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// Render generates an HTML report from the dashboard. Templates get the overall
+// leaderboard as .Trends and the per-category leaderboards as .Categories.
+func (p *HTMLPresenter) Render(writer io.Writer, dashboard *Dashboard) error {
+	p.logger.Debug("Rendering trends to HTML", "template", p.templatePath)
+
+	tmpl, err := p.parseTemplate()
+	if err != nil {
+		return err
 	}
 
-	if len(trends) == 0 {
+	if len(dashboard.Overall.Trends) == 0 {
 		p.logger.Info("No trends to render, rendering empty state")
 		// Fallback for empty trends, though the template itself handles this
 		data := map[string]interface{}{
@@ -51,43 +62,18 @@ func (p *HTMLPresenter) Render(writer io.Writer, trends []*domain.Trend) error {
 		return tmpl.Execute(writer, data)
 	}
 
-	period := trends[0].Period
-	trendIcon := "7d"
-	switch period {
-	case domain.TrendDaily:
-		trendIcon = "24h"
-	case domain.TrendWeekly:
-		trendIcon = "7d"
-	case domain.TrendMonthly:
-		trendIcon = "30d"
-	}
-
-	type TemplateTrend struct {
-		Rank     int
-		RepoName string
-		Stars    int
-		Diff     int
-	}
-
 	templateData := struct {
 		Period      string
 		TrendIcon   string
 		GeneratedAt string
-		Trends      []TemplateTrend
+		Trends      []templateTrend
+		Categories  []templateLeaderboard
 	}{
-		Period:      string(period),
-		TrendIcon:   trendIcon,
+		Period:      string(dashboard.Period),
+		TrendIcon:   trendIcon(dashboard.Period),
 		GeneratedAt: time.Now().Format(time.RFC1123),
-		Trends:      make([]TemplateTrend, len(trends)),
-	}
-
-	for i, t := range trends {
-		templateData.Trends[i] = TemplateTrend{
-			Rank:     i + 1,
-			RepoName: t.Repository.FullName,
-			Stars:    t.Repository.Stars,
-			Diff:     t.Diff,
-		}
+		Trends:      newTemplateTrends(p.webURL, dashboard.Overall.Trends),
+		Categories:  newTemplateLeaderboards(p.webURL, dashboard.Categories),
 	}
 
 	if err := tmpl.Execute(writer, templateData); err != nil {
@@ -6,82 +6,81 @@ import (
 	"log/slog"
 	"strings"
 	"text/template"
-
-	"github.com/yourname/go-trendboard/internal/domain"
 )
 
 const markdownTemplate = `
-# Go OSS Trending ({{ .Period }})
-
+{{- define "table" }}
 | Rank | Repository | Stars | Trend ({{ .TrendIcon }}) |
 |:----:|:-----------|:------|:-----------|
 {{- range .Trends }}
-| {{ .Rank }} | [{{ .RepoName }}](https://github.com/{{ .RepoName }}) | {{ .Stars }} | {{ .Diff }} ★ |
+| {{ .Rank }} | [{{ escape .RepoName }}]({{ .RepoURL }}) | {{ .Stars }}{{ if .Stale }} (stale){{ end }} | {{ .Diff }} ★ |
+{{- end }}
+{{- end -}}
+# Go OSS Trending ({{ .Period }})
+{{ template "table" . }}
+{{- $icon := .TrendIcon }}
+{{- range .Categories }}
+
+## {{ escape .Category }}
+{{ template "table" (table .Trends $icon) }}
 {{- end }}
 `
 
+// markdownEscaper escapes the characters of user-provided text, such as display names and tags,
+// that would otherwise end a table cell or link text early.
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "|", `\|`, "[", `\[`, "]", `\]`)
+
 // MarkdownPresenter renders trend data as a Markdown table.
 type MarkdownPresenter struct {
+	webURL string
 	logger *slog.Logger
 }
 
 // NewMarkdownPresenter creates a new MarkdownPresenter.
-func NewMarkdownPresenter(logger *slog.Logger) *MarkdownPresenter {
+// Repository links are built from webURL, the root URL of the GitHub web interface.
+func NewMarkdownPresenter(webURL string, logger *slog.Logger) *MarkdownPresenter {
 	return &MarkdownPresenter{
+		webURL: webURL,
 		logger: logger.With("component", "markdown_presenter"),
 	}
 }
 
-// Render generates a Markdown report from the trend data.
-func (p *MarkdownPresenter) Render(writer io.Writer, trends []*domain.Trend) error {
+// Render generates a Markdown report from the dashboard: the overall leaderboard
+// followed by a section per category.
+func (p *MarkdownPresenter) Render(writer io.Writer, dashboard *Dashboard) error {
 	p.logger.Debug("Rendering trends to Markdown")
 
-	if len(trends) == 0 {
+	if len(dashboard.Overall.Trends) == 0 {
 		p.logger.Info("No trends to render, writing empty message")
 		_, err := writer.Write([]byte("# Go OSS Trending\n\nNo trending data available.\n"))
 		return err
 	}
 
 	// Prepare data for the template
-	period := trends[0].Period
-	trendIcon := "7d"
-	switch period {
-	case domain.TrendDaily:
-		trendIcon = "24h"
-	case domain.TrendWeekly:
-		trendIcon = "7d"
-	case domain.TrendMonthly:
-		trendIcon = "30d"
-	}
-
-	type TemplateTrend struct {
-		Rank     int
-		RepoName string
-		Stars    int
-		Diff     int
-	}
-	
 	templateData := struct {
-		Period    string
-		TrendIcon string
-		Trends    []TemplateTrend
+		Period     string
+		TrendIcon  string
+		Trends     []templateTrend
+		Categories []templateLeaderboard
 	}{
-		Period:    string(period),
-		TrendIcon: trendIcon,
-		Trends:    make([]TemplateTrend, len(trends)),
-	}
-
-	for i, t := range trends {
-		templateData.Trends[i] = TemplateTrend{
-			Rank:     i + 1,
-			RepoName: t.Repository.FullName,
-			Stars:    t.Repository.Stars,
-			Diff:     t.Diff,
-		}
+		Period:     string(dashboard.Period),
+		TrendIcon:  trendIcon(dashboard.Period),
+		Trends:     newTemplateTrends(p.webURL, dashboard.Overall.Trends),
+		Categories: newTemplateLeaderboards(p.webURL, dashboard.Categories),
 	}
 
 	// Use text/template for simple replacements
-	tmpl, err := template.New("markdown").Parse(strings.TrimSpace(markdownTemplate))
+	funcs := template.FuncMap{
+		"escape": markdownEscaper.Replace,
+		// table builds the data of a "table" template invocation.
+		"table": func(trends []templateTrend, icon string) any {
+			return struct {
+				TrendIcon string
+				Trends    []templateTrend
+			}{icon, trends}
+		},
+	}
+	tmpl, err := template.New("markdown").Funcs(funcs).Parse(strings.TrimSpace(markdownTemplate))
 	if err != nil {
 		p.logger.Error("Failed to parse markdown template", "error", err)
 		return fmt.Errorf("failed to parse markdown template: %w", err)
@@ -92,6 +91,6 @@ func (p *MarkdownPresenter) Render(writer io.Writer, trends []*domain.Trend) err
 		return fmt.Errorf("failed to render markdown: %w", err)
 	}
 
-	p.logger.Info("Successfully rendered Markdown report")
+	p.logger.Info("Successfully rendered Markdown report", "categories", len(dashboard.Categories))
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"strings"
 
 	"github.com/yourname/go-trendboard/internal/config"
 	"github.com/yourname/go-trendboard/internal/domain"
@@ -11,7 +12,7 @@ import (
 
 // Presenter defines the interface for rendering trend data into a dashboard.
 type Presenter interface {
-	Render(writer io.Writer, trends []*domain.Trend) error
+	Render(writer io.Writer, dashboard *Dashboard) error
 }
 
 // NewPresenter is a factory function that returns the appropriate presenter
@@ -19,10 +20,86 @@ type Presenter interface {
 func NewPresenter(cfg *config.Config, logger *slog.Logger) (Presenter, error) {
 	switch cfg.DashboardFormat {
 	case "md", "markdown":
-		return NewMarkdownPresenter(logger), nil
+		return NewMarkdownPresenter(cfg.GitHubWebURL(), logger), nil
 	case "html":
-		return NewHTMLPresenter(cfg.DashboardTemplatePath, logger)
+		return NewHTMLPresenter(cfg.DashboardTemplatePath, cfg.GitHubWebURL(), logger)
 	default:
 		return nil, fmt.Errorf("unknown dashboard format: %s", cfg.DashboardFormat)
 	}
 }
+
+// Validate checks that a presenter can be created from the configuration
+// and, for HTML dashboards, that the template parses.
+func Validate(cfg *config.Config, logger *slog.Logger) error {
+	p, err := NewPresenter(cfg, logger)
+	if err != nil {
+		return err
+	}
+	if html, ok := p.(*HTMLPresenter); ok {
+		if _, err := html.parseTemplate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// templateTrend is a ranked row of a leaderboard as exposed to templates.
+type templateTrend struct {
+	Rank     int
+	RepoName string
+	RepoURL  string
+	Stars    int
+	Diff     int
+	Stale    bool
+}
+
+// templateLeaderboard is a per-category leaderboard as exposed to templates.
+type templateLeaderboard struct {
+	Category string
+	Trends   []templateTrend
+}
+
+// newTemplateTrends ranks trends for templates, linking repositories under webURL.
+func newTemplateTrends(webURL string, trends []*domain.Trend) []templateTrend {
+	rows := make([]templateTrend, len(trends))
+	for i, t := range trends {
+		rows[i] = templateTrend{
+			Rank:     i + 1,
+			RepoName: t.Label(),
+			RepoURL:  repoURL(webURL, t.Repository.FullName),
+			Stars:    t.Repository.Stars,
+			Diff:     t.Diff,
+			Stale:    t.Repository.Stale,
+		}
+	}
+	return rows
+}
+
+// newTemplateLeaderboards converts the per-category leaderboards of a dashboard for templates.
+func newTemplateLeaderboards(webURL string, boards []Leaderboard) []templateLeaderboard {
+	leaderboards := make([]templateLeaderboard, len(boards))
+	for i, board := range boards {
+		leaderboards[i] = templateLeaderboard{
+			Category: board.Category,
+			Trends:   newTemplateTrends(webURL, board.Trends),
+		}
+	}
+	return leaderboards
+}
+
+// trendIcon returns the short label of a trend period shown in table headers.
+func trendIcon(period domain.TrendPeriod) string {
+	switch period {
+	case domain.TrendDaily:
+		return "24h"
+	case domain.TrendMonthly:
+		return "30d"
+	default:
+		return "7d"
+	}
+}
+
+// repoURL returns the web URL of a repository under the given GitHub web root.
+func repoURL(webURL, fullName string) string {
+	return strings.TrimSuffix(webURL, "/") + "/" + fullName
+}
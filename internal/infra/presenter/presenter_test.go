@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -26,19 +27,64 @@ func getTestTrends(t *testing.T) []*domain.Trend {
 
 func TestMarkdownPresenter_Render(t *testing.T) {
 	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
-	presenter := NewMarkdownPresenter(logger)
+	presenter := NewMarkdownPresenter("https://github.com/", logger)
 	trends := getTestTrends(t)
 
 	var buf bytes.Buffer
-	err := presenter.Render(&buf, trends)
+	err := presenter.Render(&buf, NewDashboard(domain.TrendWeekly, trends, nil))
 	require.NoError(t, err)
 
 	output := buf.String()
-	assert.Contains(t, output, "# Go OSS Trending (Weekly)")
+	assert.True(t, strings.HasPrefix(output, "# Go OSS Trending (Weekly)\n\n| Rank |"))
 	assert.Contains(t, output, "| 1 | [owner/repo1](https://github.com/owner/repo1) | 1000 | 50 ★ |")
 	assert.Contains(t, output, "| 2 | [owner/repo2](https://github.com/owner/repo2) | 2500 | 25 ★ |")
 }
 
+func TestMarkdownPresenter_Render_EnterpriseLinks(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+	cfg := &config.Config{DashboardFormat: "md", GitHubBaseURL: "https://ghes.example.com/api/v3/"}
+	presenter, err := NewPresenter(cfg, logger)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, presenter.Render(&buf, NewDashboard(domain.TrendWeekly, getTestTrends(t), nil)))
+	assert.Contains(t, buf.String(), "[owner/repo1](https://ghes.example.com/owner/repo1)")
+}
+
+func TestMarkdownPresenter_Render_Categories(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+	presenter := NewMarkdownPresenter("https://github.com/", logger)
+	trends := getTestTrends(t)
+	trends[0].Target = &domain.TargetRepo{Name: "owner/repo1", DisplayName: "Repo One", Tags: []string{"web"}}
+	trends[1].Target = &domain.TargetRepo{Name: "owner/repo2", Tags: []string{"orm", "web"}}
+
+	var buf bytes.Buffer
+	require.NoError(t, presenter.Render(&buf, NewDashboard(domain.TrendWeekly, trends, nil)))
+
+	output := buf.String()
+	assert.Contains(t, output, "| 1 | [Repo One](https://github.com/owner/repo1) | 1000 | 50 ★ |")
+	assert.Contains(t, output, "## orm\n\n| Rank | Repository | Stars | Trend (7d) |")
+	assert.Contains(t, output, "## web")
+	assert.Less(t, strings.Index(output, "## orm"), strings.Index(output, "## web"))
+	ormSection := output[strings.Index(output, "## orm"):strings.Index(output, "## web")]
+	assert.Contains(t, ormSection, "| 1 | [owner/repo2](https://github.com/owner/repo2) | 2500 | 25 ★ |")
+	assert.NotContains(t, ormSection, "Repo One")
+}
+
+func TestMarkdownPresenter_Render_EscapesText(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+	presenter := NewMarkdownPresenter("https://github.com/", logger)
+	trends := getTestTrends(t)
+	trends[0].Target = &domain.TargetRepo{Name: "owner/repo1", DisplayName: `A|B [beta] C:\`, Tags: []string{"web|api"}}
+
+	var buf bytes.Buffer
+	require.NoError(t, presenter.Render(&buf, NewDashboard(domain.TrendWeekly, trends, nil)))
+
+	output := buf.String()
+	assert.Contains(t, output, `| 1 | [A\|B \[beta\] C:\\](https://github.com/owner/repo1) | 1000 |`)
+	assert.Contains(t, output, `## web\|api`)
+}
+
 func TestHTMLPresenter_Render(t *testing.T) {
 	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
 	trends := getTestTrends(t)
@@ -55,11 +101,11 @@ func TestHTMLPresenter_Render(t *testing.T) {
 	err := os.WriteFile(templatePath, []byte(templateContent), 0644)
 	require.NoError(t, err)
 
-	presenter, err := NewHTMLPresenter(templatePath, logger)
+	presenter, err := NewHTMLPresenter(templatePath, "https://github.com/", logger)
 	require.NoError(t, err)
 
 	var buf bytes.Buffer
-	err = presenter.Render(&buf, trends)
+	err = presenter.Render(&buf, NewDashboard(domain.TrendWeekly, trends, nil))
 	require.NoError(t, err)
 
 	output := buf.String()
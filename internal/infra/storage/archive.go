@@ -0,0 +1,299 @@
+package storage
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// archiveDirName is the name of the directory in the data directory that holds the monthly
+// archives written by Compact.
+const archiveDirName = "archive"
+
+// archiveSuffix ends the file names of the monthly archives, which are named after their month,
+// such as "2025-01.jsonl.gz". An archive is a gzip-compressed JSON Lines file with one snapshot
+// per line, each in the format of the daily data files plus its date.
+const archiveSuffix = ".jsonl.gz"
+
+// monthLayout is the format of the months in the names of the archives.
+const monthLayout = "2006-01"
+
+// archivedSnapshot is a line of an archive.
+type archivedSnapshot struct {
+	Date string `json:"date"`
+	*snapshotFile
+}
+
+// cachedArchive holds the lines of an archive by date ("2006-01-02"), kept in memory until the
+// archive changes. Lines are decoded on every read, so that callers never share snapshots.
+type cachedArchive struct {
+	size    int64
+	modTime time.Time
+	lines   map[string][]byte
+}
+
+// getArchiveDir returns the path to the directory of the archives.
+func (fs *FileStorer) getArchiveDir() string {
+	return filepath.Join(fs.cfg.DataDirPath, archiveDirName)
+}
+
+// getArchivePath returns the path to the archive of the month of date.
+func (fs *FileStorer) getArchivePath(date time.Time) string {
+	return filepath.Join(fs.getArchiveDir(), date.Format(monthLayout)+archiveSuffix)
+}
+
+// archivedDates returns the dates of the snapshots in the archives, in ascending order.
+func (fs *FileStorer) archivedDates() ([]time.Time, error) {
+	entries, err := os.ReadDir(fs.getArchiveDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		fs.logger.Error("Failed to read archive directory", "path", fs.getArchiveDir(), "error", err)
+		return nil, fmt.Errorf("could not read archive directory '%s': %w", fs.getArchiveDir(), err)
+	}
+
+	var dates []time.Time
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), archiveSuffix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if _, err := time.Parse(monthLayout, name); err != nil {
+			continue
+		}
+		lines, err := fs.readArchive(filepath.Join(fs.getArchiveDir(), entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, day := range slices.Sorted(maps.Keys(lines)) {
+			date, _ := time.Parse(dateLayout, day) // Dates are validated when the archive is read.
+			dates = append(dates, date)
+		}
+	}
+	return dates, nil
+}
+
+// readArchivedLine reads the line of the snapshot of date from the archive of its month.
+// It returns ErrDataNotFound if the archive doesn't hold it.
+func (fs *FileStorer) readArchivedLine(date time.Time) ([]byte, error) {
+	lines, err := fs.readArchive(fs.getArchivePath(date))
+	if err != nil {
+		return nil, err
+	}
+	line, ok := lines[date.Format(dateLayout)]
+	if !ok {
+		return nil, ErrDataNotFound
+	}
+	return line, nil
+}
+
+// readArchive reads the lines of the archive at path by date, decompressing it only if it changed
+// since it was last read. A missing archive has no lines.
+func (fs *FileStorer) readArchive(path string) (map[string][]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		fs.logger.Error("Failed to stat archive", "path", path, "error", err)
+		return nil, fmt.Errorf("could not stat archive '%s': %w", path, err)
+	}
+
+	fs.archivesMu.Lock()
+	defer fs.archivesMu.Unlock()
+	if cached, ok := fs.archives[path]; ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached.lines, nil
+	}
+
+	fs.logger.Debug("Reading archive", "path", path)
+	lines, err := decodeArchive(path)
+	if err != nil {
+		fs.logger.Error("Failed to decode archive", "path", path, "error", err)
+		return nil, fmt.Errorf("could not decode archive '%s': %w", path, err)
+	}
+	if fs.archives == nil {
+		fs.archives = make(map[string]*cachedArchive)
+	}
+	fs.archives[path] = &cachedArchive{size: info.Size(), modTime: info.ModTime(), lines: lines}
+	return lines, nil
+}
+
+// decodeArchive decompresses the archive at path and splits it into lines by date.
+func decodeArchive(path string) (map[string][]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	lines := make(map[string][]byte)
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(nil, 64<<20) // A line holds a whole snapshot.
+	for line := 1; scanner.Scan(); line++ {
+		var header struct {
+			Date string `json:"date"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if _, err := time.Parse(dateLayout, header.Date); err != nil {
+			return nil, fmt.Errorf("line %d: invalid date '%s'", line, header.Date)
+		}
+		lines[header.Date] = slices.Clone(scanner.Bytes())
+	}
+	return lines, scanner.Err()
+}
+
+// writeArchive atomically writes the lines to the archive at path, in chronological order.
+// The archive is removed if there are no lines left.
+func writeArchive(path string, lines map[string][]byte) error {
+	if len(lines) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, func(w io.Writer) error {
+		gz := gzip.NewWriter(w)
+		for _, day := range slices.Sorted(maps.Keys(lines)) {
+			if _, err := gz.Write(lines[day]); err != nil {
+				return err
+			}
+			if _, err := gz.Write([]byte{'\n'}); err != nil {
+				return err
+			}
+		}
+		return gz.Close()
+	})
+}
+
+// saveArchived rewrites the snapshot of date in the archive of its month, if the archive holds
+// it and there is no daily data file for date. It reports whether the snapshot was archived.
+func (fs *FileStorer) saveArchived(date time.Time, file *snapshotFile) (bool, error) {
+	if _, err := os.Stat(fs.getDailyDataPath(date)); !os.IsNotExist(err) {
+		return false, nil
+	}
+	path := fs.getArchivePath(date)
+	saved, err := fs.readArchive(path)
+	if err != nil {
+		return false, err
+	}
+	day := date.Format(dateLayout)
+	if _, ok := saved[day]; !ok {
+		return false, nil
+	}
+
+	lines := maps.Clone(saved)
+	if lines[day], err = json.Marshal(archivedSnapshot{Date: day, snapshotFile: file}); err != nil {
+		return false, fmt.Errorf("could not encode snapshot of %s: %w", day, err)
+	}
+	if err := writeArchive(path, lines); err != nil {
+		fs.logger.Error("Failed to write archive", "path", path, "error", err)
+		return false, fmt.Errorf("could not write archive '%s': %w", path, err)
+	}
+	fs.logger.Info("Successfully saved archived data", "path", path, "date", day)
+	return true, nil
+}
+
+// Compact moves the snapshots of the dates in archive into the compressed archives of their
+// months, and deletes the snapshots of the dates in drop, whether they were archived already or not.
+// It returns the number of data files that were archived and of snapshots that were deleted.
+// Archives are written before the daily data files are removed, so that an interrupted compaction
+// loses nothing: a daily data file takes precedence over the archived copy of its snapshot.
+func (fs *FileStorer) Compact(archive, drop []time.Time) (archived, dropped int, err error) {
+	unlock, err := fs.lock(true)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer unlock()
+
+	toArchive := make(map[string]bool, len(archive))
+	for _, date := range archive {
+		toArchive[date.Format(dateLayout)] = true
+	}
+	months := make(map[string][]time.Time)
+	for _, date := range slices.Concat(archive, drop) {
+		months[date.Format(monthLayout)] = append(months[date.Format(monthLayout)], date)
+	}
+
+	for _, month := range slices.Sorted(maps.Keys(months)) {
+		path := fs.getArchivePath(months[month][0])
+		saved, err := fs.readArchive(path)
+		if err != nil {
+			return archived, dropped, err
+		}
+		lines := maps.Clone(saved)
+		if lines == nil {
+			lines = make(map[string][]byte)
+		}
+
+		var moved []string
+		changed := false
+		for _, date := range months[month] {
+			day := date.Format(dateLayout)
+			dataPath := fs.getDailyDataPath(date)
+			_, statErr := os.Stat(dataPath)
+			hasFile := statErr == nil
+			if !toArchive[day] {
+				if _, ok := lines[day]; ok || hasFile {
+					delete(lines, day)
+					changed = true
+					dropped++
+				}
+				if hasFile {
+					moved = append(moved, dataPath)
+				}
+				continue
+			}
+			if !hasFile {
+				continue // Archived already.
+			}
+			file, err := fs.readSnapshot(date)
+			if err != nil {
+				return archived, dropped, err
+			}
+			// Snapshots are archived in the current format, even if their daily data file wasn't upgraded.
+			file = newSnapshotFile(file.Repositories, file.Fetcher, file.ToolVersion)
+			if lines[day], err = json.Marshal(archivedSnapshot{Date: day, snapshotFile: file}); err != nil {
+				return archived, dropped, fmt.Errorf("could not encode snapshot of %s: %w", day, err)
+			}
+			changed = true
+			moved = append(moved, dataPath)
+			archived++
+		}
+		if !changed {
+			continue
+		}
+
+		if err := writeArchive(path, lines); err != nil {
+			fs.logger.Error("Failed to write archive", "path", path, "error", err)
+			return archived, dropped, fmt.Errorf("could not write archive '%s': %w", path, err)
+		}
+		fs.logger.Info("Successfully wrote archive", "path", path, "count", len(lines))
+		for _, dataPath := range moved {
+			if err := os.Remove(dataPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+				fs.logger.Error("Failed to remove data file", "path", dataPath, "error", err)
+				return archived, dropped, fmt.Errorf("could not remove data file '%s': %w", dataPath, err)
+			}
+		}
+	}
+	return archived, dropped, nil
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+func TestFileStorer_Compact(t *testing.T) {
+	storer, cfg := setupTestStorer(t)
+	jan30 := time.Date(2025, 1, 30, 0, 0, 0, 0, time.UTC)
+	jan31 := jan30.AddDate(0, 0, 1)
+	feb1 := jan31.AddDate(0, 0, 1)
+	feb2 := feb1.AddDate(0, 0, 1)
+	repos := func(stars int) []*domain.Repository {
+		return []*domain.Repository{{ID: 1, FullName: "owner/repo", Stars: stars, Status: domain.StatusOK}}
+	}
+
+	require.NoError(t, os.MkdirAll(cfg.DataDirPath, 0755))
+	require.NoError(t, os.WriteFile(storer.getDailyDataPath(jan30), []byte(`[{"FullName": "owner/repo", "Stars": 10}]`), 0644))
+	require.NoError(t, storer.Save(jan31, repos(11)))
+	require.NoError(t, storer.Save(feb1, repos(12)))
+	require.NoError(t, storer.Save(feb2, repos(13)))
+
+	archived, dropped, err := storer.Compact([]time.Time{jan30, feb1}, []time.Time{jan31})
+	require.NoError(t, err)
+	assert.Equal(t, 2, archived)
+	assert.Equal(t, 1, dropped)
+
+	for _, date := range []time.Time{jan30, jan31, feb1} {
+		assert.NoFileExists(t, storer.getDailyDataPath(date))
+	}
+	assert.FileExists(t, storer.getArchivePath(jan30))
+	assert.FileExists(t, storer.getArchivePath(feb1))
+
+	dates, err := storer.ListDates()
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{jan30, feb1, feb2}, dates)
+
+	loaded, err := storer.Load(jan30)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Repository{{FullName: "owner/repo", Stars: 10}}, loaded)
+	_, err = storer.Load(jan31)
+	assert.ErrorIs(t, err, ErrDataNotFound)
+
+	snapshots, err := storer.LoadRange(jan30, feb2)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Snapshot{
+		{Date: jan30, Repositories: []*domain.Repository{{FullName: "owner/repo", Stars: 10}}},
+		{Date: feb1, Repositories: repos(12)},
+		{Date: feb2, Repositories: repos(13)},
+	}, snapshots)
+
+	// Compacting again changes nothing.
+	archived, dropped, err = storer.Compact([]time.Time{jan30, feb1}, []time.Time{jan31})
+	require.NoError(t, err)
+	assert.Zero(t, archived)
+	assert.Zero(t, dropped)
+
+	// Saving an archived date again rewrites its archived copy, without a daily data file.
+	require.NoError(t, storer.Save(feb1, repos(20)))
+	assert.NoFileExists(t, storer.getDailyDataPath(feb1))
+	loaded, err = storer.Load(feb1)
+	require.NoError(t, err)
+	assert.Equal(t, repos(20), loaded)
+	archived, _, err = storer.Compact([]time.Time{feb1}, nil)
+	require.NoError(t, err)
+	assert.Zero(t, archived)
+
+	// A daily data file written next to the archive, e.g. by a git pull, takes precedence over
+	// the archive until the next compaction.
+	require.NoError(t, writeJSONFileAtomic(storer.getDailyDataPath(feb1), newSnapshotFile(repos(30), "", "")))
+	loaded, err = storer.Load(feb1)
+	require.NoError(t, err)
+	assert.Equal(t, repos(30), loaded)
+	archived, _, err = storer.Compact([]time.Time{feb1}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, archived)
+	loaded, err = storer.Load(feb1)
+	require.NoError(t, err)
+	assert.Equal(t, repos(30), loaded)
+
+	// Dropping every snapshot of a month removes its archive.
+	_, dropped, err = storer.Compact(nil, []time.Time{jan30})
+	require.NoError(t, err)
+	assert.Equal(t, 1, dropped)
+	assert.NoFileExists(t, storer.getArchivePath(jan30))
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic writes the file at path with write. The content goes to a temporary file in
+// the same directory, which is synced to disk and then renamed over path, so a crash or a full
+// disk in the middle of the write leaves the previous file intact instead of a truncated one.
+func writeFileAtomic(path string, write func(w io.Writer) error) (err error) {
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	tmp, err := os.CreateTemp(dir, "."+name+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("could not create temporary file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if err := write(tmp); err != nil {
+		return err
+	}
+	// CreateTemp creates the file with mode 0600, while the data files are meant to be shared.
+	if err := tmp.Chmod(0644); err != nil {
+		return fmt.Errorf("could not set permissions of temporary file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("could not sync temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("could not close temporary file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("could not replace file: %w", err)
+	}
+	syncDir(dir)
+	return nil
+}
+
+// writeJSONFileAtomic atomically writes v to path as indented JSON for human-readability.
+func writeJSONFileAtomic(path string, v any) error {
+	return writeFileAtomic(path, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	})
+}
+
+// syncDir syncs the directory dir so that a rename in it survives a crash. It is best-effort,
+// as some platforms, such as Windows, can't sync directories.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
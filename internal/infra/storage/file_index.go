@@ -0,0 +1,251 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+// defaultIndexDir is the directory in the user cache directory that holds the indexes of the
+// data directories when no index cache directory is configured.
+const defaultIndexDir = "go-trendboard/index"
+
+// fileIndex caches summaries of the snapshots of a month by date ("2006-01-02"), so that history
+// queries don't reopen every data file. Each month of a data directory has its own index, so that
+// a query only reads the indexes of the months it covers. Indexes live outside the data directory,
+// which is usually committed, and can be deleted at any time; they are rebuilt on the next query.
+type fileIndex struct {
+	Files map[string]*indexedFile `json:"files"`
+}
+
+// indexedFile is the summary of a snapshot: the value of each repository for the day, without
+// its intra-day samples. Size and ModTime are those of the file the snapshot was read from, the
+// archive of its month for an archived snapshot, and tell cheaply that it didn't change. When they
+// don't match, e.g. after a git pull, which resets modification times, Hash, the SHA-256 of the
+// snapshot's data, tells whether it actually changed.
+type indexedFile struct {
+	Size    int64                `json:"size"`
+	ModTime time.Time            `json:"mod_time"`
+	Hash    string               `json:"hash"`
+	Repos   []*domain.Repository `json:"repos"`
+}
+
+// getIndexDir returns the path to the directory holding the indexes of the data directory, or ""
+// if there is no cache directory to keep them in. Data directories sharing a cache directory are
+// told apart by a hash of their absolute path.
+func (fs *FileStorer) getIndexDir() string {
+	base := fs.cfg.IndexCacheDir
+	if base == "" {
+		cacheDir, err := os.UserCacheDir()
+		if err != nil {
+			return ""
+		}
+		base = filepath.Join(cacheDir, defaultIndexDir)
+	}
+	dataDir, err := filepath.Abs(fs.cfg.DataDirPath)
+	if err != nil {
+		dataDir = fs.cfg.DataDirPath
+	}
+	sum := sha256.Sum256([]byte(dataDir))
+	return filepath.Join(base, hex.EncodeToString(sum[:8]))
+}
+
+// LoadRange loads the snapshots of the dates from from to to, both inclusive, through the indexes
+// of their months.
+func (fs *FileStorer) LoadRange(from, to time.Time) ([]domain.Snapshot, error) {
+	unlock, err := fs.lock(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	dates, err := fs.ListDates()
+	if err != nil {
+		return nil, err
+	}
+	first, last := from.Format(dateLayout), to.Format(dateLayout)
+	present := make(map[string]bool, len(dates))
+	months := make(map[string][]time.Time)
+	for _, date := range dates {
+		day := date.Format(dateLayout)
+		present[day] = true
+		if day >= first && day <= last {
+			months[date.Format(monthLayout)] = append(months[date.Format(monthLayout)], date)
+		}
+	}
+
+	var snapshots []domain.Snapshot
+	for _, month := range slices.Sorted(maps.Keys(months)) {
+		loaded, err := fs.loadIndexedMonth(month, months[month], present)
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, loaded...)
+	}
+	if snapshots == nil {
+		snapshots = []domain.Snapshot{}
+	}
+	return snapshots, nil
+}
+
+// loadIndexedMonth loads the summaries of the snapshots of dates, all in month, from the index
+// of the month. Only snapshots whose file changed since they were indexed are read again, and
+// only those whose data changed are decoded. Entries of dates missing from present, the dates
+// of every snapshot, are dropped. The refreshed index is written back on a best-effort basis.
+func (fs *FileStorer) loadIndexedMonth(month string, dates []time.Time, present map[string]bool) ([]domain.Snapshot, error) {
+	index := &fileIndex{}
+	var path string
+	if dir := fs.getIndexDir(); dir != "" {
+		path = filepath.Join(dir, month+".json")
+		if data, err := os.ReadFile(path); err == nil {
+			if err := json.Unmarshal(data, index); err != nil {
+				fs.logger.Warn("Ignoring corrupt index of data files", "path", path, "error", err)
+				index = &fileIndex{}
+			}
+		} else if !os.IsNotExist(err) {
+			fs.logger.Warn("Failed to read index of data files", "path", path, "error", err)
+		}
+	}
+	if index.Files == nil {
+		index.Files = make(map[string]*indexedFile)
+	}
+
+	changed := false
+	for day := range index.Files {
+		if !present[day] {
+			delete(index.Files, day)
+			changed = true
+		}
+	}
+	snapshots := make([]domain.Snapshot, 0, len(dates))
+	for _, date := range dates {
+		day := date.Format(dateLayout)
+		info, err := fs.statSnapshot(date)
+		if err != nil {
+			return nil, err
+		}
+		cached, ok := index.Files[day]
+		if !ok || cached.Size != info.Size() || !cached.ModTime.Equal(info.ModTime()) {
+			if cached, err = fs.indexSnapshot(date, info, cached); err != nil {
+				return nil, err
+			}
+			index.Files[day] = cached
+			changed = true
+		}
+		snapshots = append(snapshots, domain.Snapshot{Date: date, Repositories: cached.Repos})
+	}
+
+	if changed && path != "" {
+		// Readers share the lock, so concurrent readers may both write the index. Replacing it
+		// atomically keeps it intact, whichever of them writes last.
+		write := func(w io.Writer) error { return json.NewEncoder(w).Encode(index) }
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = writeFileAtomic(path, write)
+		}
+		if err != nil {
+			fs.logger.Warn("Failed to write index of data files", "path", path, "error", err)
+		}
+	}
+	return snapshots, nil
+}
+
+// statSnapshot returns the file information of the file holding the snapshot of date: its daily
+// data file if there is one, which takes precedence over the archives, and the archive of its
+// month otherwise.
+func (fs *FileStorer) statSnapshot(date time.Time) (os.FileInfo, error) {
+	path := fs.getDailyDataPath(date)
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		path = fs.getArchivePath(date)
+		info, err = os.Stat(path)
+	}
+	if err != nil {
+		fs.logger.Error("Failed to stat data file", "path", path, "error", err)
+		return nil, fmt.Errorf("could not stat data file '%s': %w", path, err)
+	}
+	return info, nil
+}
+
+// indexSnapshot reads the snapshot of date, whose file has the information info, and returns its
+// index entry. The summary of cached, the previous entry if any, is kept if the data didn't change.
+func (fs *FileStorer) indexSnapshot(date time.Time, info os.FileInfo, cached *indexedFile) (*indexedFile, error) {
+	data, path, err := fs.readSnapshotData(date)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	entry := &indexedFile{Size: info.Size(), ModTime: info.ModTime(), Hash: hex.EncodeToString(sum[:])}
+	if cached != nil && cached.Hash == entry.Hash {
+		entry.Repos = cached.Repos
+		return entry, nil
+	}
+
+	file, err := decodeSnapshotFile(data)
+	if err != nil {
+		fs.logger.Error("Failed to decode JSON data", "path", path, "date", date.Format(dateLayout), "error", err)
+		return nil, fmt.Errorf("could not decode JSON data of %s from '%s': %w", date.Format(dateLayout), path, err)
+	}
+	for _, repo := range file.Repositories {
+		repo.Samples = nil // History queries only use the value of the day.
+	}
+	entry.Repos = file.Repositories
+	return entry, nil
+}
+
+// LoadSeries loads the history of a single repository from the index of the data files.
+func (fs *FileStorer) LoadSeries(repoName string, from, to time.Time) ([]domain.SeriesPoint, error) {
+	snapshots, err := fs.LoadRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return seriesOf(repoName, snapshots), nil
+}
+
+// LoadLatest loads the most recent snapshot. The data directory stays locked from listing the
+// dates to loading the latest one, so that a concurrent compaction can't remove it in between.
+func (fs *FileStorer) LoadLatest() (*domain.Snapshot, error) {
+	unlock, err := fs.lock(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	dates, err := fs.ListDates()
+	if err != nil {
+		return nil, err
+	}
+	if len(dates) == 0 {
+		return nil, ErrDataNotFound
+	}
+	latest := dates[len(dates)-1]
+	repos, err := fs.load(latest)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Snapshot{Date: latest, Repositories: repos}, nil
+}
+
+// seriesOf extracts the points of the repository repoName, matched ignoring case, from snapshots.
+func seriesOf(repoName string, snapshots []domain.Snapshot) []domain.SeriesPoint {
+	var series []domain.SeriesPoint
+	for _, snapshot := range snapshots {
+		for _, repo := range snapshot.Repositories {
+			if strings.EqualFold(repo.FullName, repoName) {
+				series = append(series, domain.SeriesPoint{Date: snapshot.Date, Repository: repo})
+				break
+			}
+		}
+	}
+	return series
+}
@@ -2,20 +2,29 @@ package storage
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/yourname/go-trendboard/internal/config"
 	"github.com/yourname/go-trendboard/internal/domain"
+	"github.com/yourname/go-trendboard/internal/version"
 )
 
 // FileStorer implements the Storer interface using the local file system.
 type FileStorer struct {
 	cfg    *config.Config
 	logger *slog.Logger
+
+	// archivesMu guards archives, the monthly archives read so far by path.
+	archivesMu sync.Mutex
+	archives   map[string]*cachedArchive
 }
 
 // NewFileStorer creates a new FileStorer.
@@ -28,33 +37,67 @@ func NewFileStorer(cfg *config.Config, logger *slog.Logger) *FileStorer {
 
 // getDailyDataPath returns the path to the data file for a given date.
 func (fs *FileStorer) getDailyDataPath(date time.Time) string {
-	fileName := fmt.Sprintf("%s.json", date.Format("2006-01-02"))
+	fileName := fmt.Sprintf("%s.json", date.Format(dateLayout))
 	return filepath.Join(fs.cfg.DataDirPath, fileName)
 }
 
-// Save saves repository data to a JSON file for a specific date.
+// renamesFileName is the name of the file in the data directory that records repository renames.
+const renamesFileName = "renames.json"
+
+// getRenamesPath returns the path to the file recording repository renames.
+func (fs *FileStorer) getRenamesPath() string {
+	return filepath.Join(fs.cfg.DataDirPath, renamesFileName)
+}
+
+// Save saves repository data to a JSON file for a specific date. The metadata of a snapshot
+// already saved for the date is kept, as Save rewrites snapshots rather than fetching them.
 func (fs *FileStorer) Save(date time.Time, repos []*domain.Repository) error {
-	path := fs.getDailyDataPath(date)
-	fs.logger.Debug("Saving data", "path", path)
+	unlock, err := fs.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
-	// Ensure the data directory exists.
-	if err := os.MkdirAll(fs.cfg.DataDirPath, 0755); err != nil {
-		fs.logger.Error("Failed to create data directory", "path", fs.cfg.DataDirPath, "error", err)
-		return fmt.Errorf("could not create data directory '%s': %w", fs.cfg.DataDirPath, err)
+	var fetcher, toolVersion string
+	if saved, err := fs.readSnapshot(date); err == nil {
+		fetcher, toolVersion = saved.Fetcher, saved.ToolVersion
 	}
+	return fs.save(date, newSnapshotFile(repos, fetcher, toolVersion))
+}
 
-	file, err := os.Create(path)
+// Merge merges repos into the data file of a specific date with domain.MergeSnapshot, recording
+// the configured fetcher and the running version in its metadata. The data directory stays locked
+// from reading the file to writing it back, so that concurrent updates don't lose each other's values.
+func (fs *FileStorer) Merge(date time.Time, repos []*domain.Repository) error {
+	unlock, err := fs.lock(true)
 	if err != nil {
-		fs.logger.Error("Failed to create data file", "path", path, "error", err)
-		return fmt.Errorf("could not create data file '%s': %w", path, err)
+		return err
 	}
-	defer file.Close()
+	defer unlock()
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ") // for human-readability
-	if err := encoder.Encode(repos); err != nil {
-		fs.logger.Error("Failed to encode data to JSON", "path", path, "error", err)
-		return fmt.Errorf("could not encode data to '%s': %w", path, err)
+	var saved []*domain.Repository
+	if file, err := fs.readSnapshot(date); err == nil {
+		saved = file.Repositories
+	} else if !errors.Is(err, ErrDataNotFound) {
+		return err
+	}
+	return fs.save(date, newSnapshotFile(domain.MergeSnapshot(saved, repos), fs.cfg.Fetcher, version.Current()))
+}
+
+// save writes the data file of a specific date without locking the data directory. A snapshot
+// that was moved into an archive is rewritten there, so that saving it doesn't bring its daily
+// data file back next to the archive.
+func (fs *FileStorer) save(date time.Time, file *snapshotFile) error {
+	if archived, err := fs.saveArchived(date, file); archived || err != nil {
+		return err
+	}
+
+	path := fs.getDailyDataPath(date)
+	fs.logger.Debug("Saving data", "path", path)
+
+	if err := writeJSONFileAtomic(path, file); err != nil {
+		fs.logger.Error("Failed to write data file", "path", path, "error", err)
+		return fmt.Errorf("could not write data file '%s': %w", path, err)
 	}
 
 	fs.logger.Info("Successfully saved data", "path", path)
@@ -62,33 +105,135 @@ func (fs *FileStorer) Save(date time.Time, repos []*domain.Repository) error {
 }
 
 // Load loads repository data from a JSON file for a specific date.
+// Files in the legacy format, a bare array of repositories, are read as well.
 func (fs *FileStorer) Load(date time.Time) ([]*domain.Repository, error) {
+	unlock, err := fs.lock(false)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return fs.load(date)
+}
+
+// load loads repository data for a specific date without locking the data directory.
+func (fs *FileStorer) load(date time.Time) ([]*domain.Repository, error) {
+	file, err := fs.readSnapshot(date)
+	if err != nil {
+		if errors.Is(err, ErrDataNotFound) {
+			fs.logger.Warn("Data file not found", "path", fs.getDailyDataPath(date))
+		}
+		return nil, err
+	}
+	return file.Repositories, nil
+}
+
+// readSnapshot reads the data file of a specific date, in the current or the legacy format,
+// falling back to the archive of its month, without locking the data directory.
+func (fs *FileStorer) readSnapshot(date time.Time) (*snapshotFile, error) {
+	data, path, err := fs.readSnapshotData(date)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := decodeSnapshotFile(data)
+	if err != nil {
+		fs.logger.Error("Failed to decode JSON data", "path", path, "date", date.Format(dateLayout), "error", err)
+		return nil, fmt.Errorf("could not decode JSON data of %s from '%s': %w", date.Format(dateLayout), path, err)
+	}
+
+	fs.logger.Debug("Successfully loaded data", "path", path, "schema_version", file.SchemaVersion)
+	return file, nil
+}
+
+// readSnapshotData reads the undecoded snapshot of a specific date from its data file, or from
+// its line of the archive of its month, without locking the data directory. It also returns the
+// path of the file the snapshot was read from.
+func (fs *FileStorer) readSnapshotData(date time.Time) ([]byte, string, error) {
 	path := fs.getDailyDataPath(date)
 	fs.logger.Debug("Loading data", "path", path)
 
-	file, err := os.Open(path)
+	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
-			fs.logger.Warn("Data file not found", "path", path)
-			return nil, ErrDataNotFound
+			line, err := fs.readArchivedLine(date)
+			return line, fs.getArchivePath(date), err
 		}
-		fs.logger.Error("Failed to open data file", "path", path, "error", err)
-		return nil, fmt.Errorf("could not open data file '%s': %w", path, err)
+		fs.logger.Error("Failed to read data file", "path", path, "error", err)
+		return nil, "", fmt.Errorf("could not read data file '%s': %w", path, err)
 	}
-	defer file.Close()
+	return data, path, nil
+}
 
-	var repos []*domain.Repository
-	if err := json.NewDecoder(file).Decode(&repos); err != nil {
-		fs.logger.Error("Failed to decode JSON data", "path", path, "error", err)
-		return nil, fmt.Errorf("could not decode JSON data from '%s': %w", path, err)
+// UpgradeSchema rewrites the data files saved in an older format in the current one.
+// It returns the number of files that were upgraded.
+func (fs *FileStorer) UpgradeSchema() (int, error) {
+	unlock, err := fs.lock(true)
+	if err != nil {
+		return 0, err
 	}
+	defer unlock()
 
-	fs.logger.Debug("Successfully loaded data", "path", path)
-	return repos, nil
+	dates, err := fs.ListDates()
+	if err != nil {
+		return 0, err
+	}
+	upgraded := 0
+	for _, date := range dates {
+		file, err := fs.readSnapshot(date)
+		if err != nil {
+			return upgraded, err
+		}
+		if file.SchemaVersion == snapshotSchemaVersion {
+			continue
+		}
+		// The fetcher and version that fetched a legacy snapshot weren't recorded, so they stay unknown.
+		if err := fs.save(date, newSnapshotFile(file.Repositories, file.Fetcher, file.ToolVersion)); err != nil {
+			return upgraded, err
+		}
+		upgraded++
+	}
+	return upgraded, nil
+}
+
+// ListDates returns the dates of the data files in the data directory and of the snapshots in
+// the archives, in ascending order. Other files in the directory, such as renames.json, are ignored.
+func (fs *FileStorer) ListDates() ([]time.Time, error) {
+	entries, err := os.ReadDir(fs.cfg.DataDirPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		fs.logger.Error("Failed to read data directory", "path", fs.cfg.DataDirPath, "error", err)
+		return nil, fmt.Errorf("could not read data directory '%s': %w", fs.cfg.DataDirPath, err)
+	}
+
+	// Entries are sorted by file name, which sorts the dates too.
+	var dates []time.Time
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		if date, err := time.Parse(dateLayout, name); err == nil {
+			dates = append(dates, date)
+		}
+	}
+
+	archived, err := fs.archivedDates()
+	if err != nil {
+		return nil, err
+	}
+	if len(archived) == 0 {
+		return dates, nil
+	}
+	// A date can be both archived and saved again as a data file, which then takes precedence.
+	dates = append(dates, archived...)
+	slices.SortFunc(dates, time.Time.Compare)
+	return slices.CompactFunc(dates, time.Time.Equal), nil
 }
 
 // LoadTargetRepos loads the list of target repositories from repos.json.
-func (fs *FileStorer) LoadTargetRepos() ([]string, error) {
+func (fs *FileStorer) LoadTargetRepos() ([]domain.TargetRepo, error) {
 	path := fs.cfg.ReposFilePath
 	fs.logger.Debug("Loading target repos", "path", path)
 
@@ -103,7 +248,7 @@ func (fs *FileStorer) LoadTargetRepos() ([]string, error) {
 	}
 	defer file.Close()
 
-	var repos []string
+	var repos []domain.TargetRepo
 	if err := json.NewDecoder(file).Decode(&repos); err != nil {
 		fs.logger.Error("Failed to decode repos config file", "path", path, "error", err)
 		return nil, fmt.Errorf("could not decode repos config file '%s': %w", path, err)
@@ -113,25 +258,60 @@ func (fs *FileStorer) LoadTargetRepos() ([]string, error) {
 	return repos, nil
 }
 
-// SaveTargetRepos saves the list of target repositories to repos.json.
-func (fs *FileStorer) SaveTargetRepos(repos []string) error {
+// SaveTargetRepos saves the list of target repositories to repos.json. The file is replaced
+// atomically, but not locked, as it usually lives outside of the data directory.
+func (fs *FileStorer) SaveTargetRepos(repos []domain.TargetRepo) error {
 	path := fs.cfg.ReposFilePath
 	fs.logger.Debug("Saving target repos", "path", path)
 
-	file, err := os.Create(path)
+	if err := writeJSONFileAtomic(path, repos); err != nil {
+		fs.logger.Error("Failed to write repos config file", "path", path, "error", err)
+		return fmt.Errorf("could not write repos config file '%s': %w", path, err)
+	}
+
+	fs.logger.Info("Successfully saved target repos", "path", path, "count", len(repos))
+	return nil
+}
+
+// LoadRenames loads the recorded repository renames from renames.json in the data directory.
+func (fs *FileStorer) LoadRenames() ([]domain.Rename, error) {
+	path := fs.getRenamesPath()
+	fs.logger.Debug("Loading renames", "path", path)
+
+	file, err := os.Open(path)
 	if err != nil {
-		fs.logger.Error("Failed to create repos config file", "path", path, "error", err)
-		return fmt.Errorf("could not create repos config file '%s': %w", path, err)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		fs.logger.Error("Failed to open renames file", "path", path, "error", err)
+		return nil, fmt.Errorf("could not open renames file '%s': %w", path, err)
 	}
 	defer file.Close()
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(repos); err != nil {
-		fs.logger.Error("Failed to encode repos config to JSON", "path", path, "error", err)
-		return fmt.Errorf("could not encode repos config to '%s': %w", path, err)
+	var renames []domain.Rename
+	if err := json.NewDecoder(file).Decode(&renames); err != nil {
+		fs.logger.Error("Failed to decode renames file", "path", path, "error", err)
+		return nil, fmt.Errorf("could not decode renames file '%s': %w", path, err)
 	}
+	return renames, nil
+}
 
-	fs.logger.Info("Successfully saved target repos", "path", path, "count", len(repos))
+// SaveRenames saves the recorded repository renames to renames.json in the data directory.
+func (fs *FileStorer) SaveRenames(renames []domain.Rename) error {
+	unlock, err := fs.lock(true)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	path := fs.getRenamesPath()
+	fs.logger.Debug("Saving renames", "path", path)
+
+	if err := writeJSONFileAtomic(path, renames); err != nil {
+		fs.logger.Error("Failed to write renames file", "path", path, "error", err)
+		return fmt.Errorf("could not write renames file '%s': %w", path, err)
+	}
+
+	fs.logger.Info("Successfully saved renames", "path", path, "count", len(renames))
 	return nil
 }
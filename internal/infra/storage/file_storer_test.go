@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"errors"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -21,6 +23,7 @@ func setupTestStorer(t *testing.T) (*FileStorer, *config.Config) {
 	cfg := &config.Config{
 		DataDirPath:   filepath.Join(tempDir, "data"),
 		ReposFilePath: filepath.Join(tempDir, "repos.json"),
+		IndexCacheDir: filepath.Join(tempDir, "cache"),
 	}
 	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil)) // Discard logs
 
@@ -60,9 +63,32 @@ func TestFileStorer_Load_NotFound(t *testing.T) {
 	assert.ErrorIs(t, err, ErrDataNotFound)
 }
 
+func TestFileStorer_ListDates(t *testing.T) {
+	storer, cfg := setupTestStorer(t)
+
+	// A missing data directory has no dates.
+	dates, err := storer.ListDates()
+	require.NoError(t, err)
+	assert.Empty(t, dates)
+
+	later := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+	earlier := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, storer.Save(later, nil))
+	require.NoError(t, storer.Save(earlier, nil))
+	require.NoError(t, storer.SaveRenames(nil))
+	require.NoError(t, os.WriteFile(filepath.Join(cfg.DataDirPath, "notes.txt"), nil, 0644))
+
+	dates, err = storer.ListDates()
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{earlier, later}, dates)
+}
+
 func TestFileStorer_SaveAndLoadTargetRepos(t *testing.T) {
 	storer, _ := setupTestStorer(t)
-	targetRepos := []string{"gin-gonic/gin", "go-chi/chi"}
+	targetRepos := []domain.TargetRepo{
+		{Name: "gin-gonic/gin"},
+		{Name: "go-chi/chi", DisplayName: "chi", Tags: []string{"web"}, Notes: "lightweight router", Disabled: true},
+	}
 
 	// 1. Test Save
 	err := storer.SaveTargetRepos(targetRepos)
@@ -74,6 +100,43 @@ func TestFileStorer_SaveAndLoadTargetRepos(t *testing.T) {
 	assert.Equal(t, targetRepos, loadedTargetRepos)
 }
 
+func TestFileStorer_LoadTargetRepos_MixedForms(t *testing.T) {
+	storer, cfg := setupTestStorer(t)
+	content := `["gin-gonic/gin", {"name": "gorm/gorm", "tags": ["orm"]}]`
+	require.NoError(t, os.WriteFile(cfg.ReposFilePath, []byte(content), 0644))
+
+	targetRepos, err := storer.LoadTargetRepos()
+	require.NoError(t, err)
+	assert.Equal(t, []domain.TargetRepo{
+		{Name: "gin-gonic/gin"},
+		{Name: "gorm/gorm", Tags: []string{"orm"}},
+	}, targetRepos)
+
+	// Entries without metadata are written back in the plain string form.
+	require.NoError(t, storer.SaveTargetRepos(targetRepos))
+	saved, err := os.ReadFile(cfg.ReposFilePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(saved), `"gin-gonic/gin",`)
+	assert.Contains(t, string(saved), `"name": "gorm/gorm"`)
+}
+
+func TestFileStorer_SaveAndLoadRenames(t *testing.T) {
+	storer, _ := setupTestStorer(t)
+
+	// Nothing recorded yet is not an error.
+	renames, err := storer.LoadRenames()
+	require.NoError(t, err)
+	assert.Empty(t, renames)
+
+	detectedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	expected := []domain.Rename{{From: "old/name", To: "new/name", ID: 42, DetectedAt: detectedAt}}
+	require.NoError(t, storer.SaveRenames(expected))
+
+	renames, err = storer.LoadRenames()
+	require.NoError(t, err)
+	assert.Equal(t, expected, renames)
+}
+
 func TestFileStorer_LoadTargetRepos_NotFound(t *testing.T) {
 	storer, _ := setupTestStorer(t)
 
@@ -108,3 +171,195 @@ func TestFileStorer_LoadTargetRepos_InvalidJSON(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "could not decode repos config file")
 }
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.json")
+	require.NoError(t, os.WriteFile(path, []byte(`["original"]`), 0644))
+
+	t.Run("failed write keeps the original file", func(t *testing.T) {
+		err := writeFileAtomic(path, func(w io.Writer) error {
+			w.Write([]byte(`["trunc`))
+			return errors.New("disk full")
+		})
+		require.Error(t, err)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, `["original"]`, string(data))
+	})
+
+	t.Run("successful write replaces the file", func(t *testing.T) {
+		require.NoError(t, writeJSONFileAtomic(path, []string{"updated"}))
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.JSONEq(t, `["updated"]`, string(data))
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0644), info.Mode().Perm())
+	})
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "no temporary files should be left behind")
+	assert.Equal(t, "data.json", entries[0].Name())
+}
+
+func TestFileStorer_LockSerializesWriters(t *testing.T) {
+	storer, _ := setupTestStorer(t)
+	date := time.Date(2025, 11, 22, 0, 0, 0, 0, time.UTC)
+
+	unlock, err := storer.lock(true)
+	require.NoError(t, err)
+
+	saved := make(chan error)
+	go func() {
+		repo, _ := domain.NewRepository("owner/repo", 100)
+		saved <- storer.Save(date, []*domain.Repository{repo})
+	}()
+
+	select {
+	case err := <-saved:
+		t.Fatalf("Save returned while the data directory was locked: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	unlock()
+	require.NoError(t, <-saved)
+	repos, err := storer.Load(date)
+	require.NoError(t, err)
+	assert.Len(t, repos, 1)
+}
+
+func TestFileStorer_ReadsReadOnlyDataDir(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permissions are not enforced for root")
+	}
+	storer, cfg := setupTestStorer(t)
+	date := time.Date(2025, 11, 22, 0, 0, 0, 0, time.UTC)
+	repo, _ := domain.NewRepository("owner/repo", 100)
+	require.NoError(t, storer.Save(date, []*domain.Repository{repo}))
+
+	// Existing lock files are opened read-only for shared locks.
+	require.NoError(t, os.Chmod(storer.getLockPath(), 0444))
+	require.NoError(t, os.Chmod(cfg.DataDirPath, 0555))
+	t.Cleanup(func() { os.Chmod(cfg.DataDirPath, 0755) })
+	repos, err := storer.Load(date)
+	require.NoError(t, err)
+	assert.Len(t, repos, 1)
+
+	// Without a lock file that can be created, readers go ahead without a lock.
+	require.NoError(t, os.Chmod(cfg.DataDirPath, 0755))
+	require.NoError(t, os.Remove(storer.getLockPath()))
+	require.NoError(t, os.Chmod(cfg.DataDirPath, 0555))
+	repos, err = storer.Load(date)
+	require.NoError(t, err)
+	assert.Len(t, repos, 1)
+
+	assert.Error(t, storer.Save(date, []*domain.Repository{repo}), "writers still need the lock")
+}
+
+func TestOpenLockFile_SharedIsReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), lockFileName)
+
+	file, err := openLockFile(path, false)
+	require.NoError(t, err)
+	file.Close()
+
+	file, err = openLockFile(path, false)
+	require.NoError(t, err)
+	defer file.Close()
+	_, err = file.Write([]byte("x"))
+	assert.Error(t, err, "an existing lock file is opened read-only for a shared lock")
+}
+
+func TestDecodeSnapshotFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *snapshotFile
+		wantErr string
+	}{
+		{
+			name: "legacy array",
+			data: `[{"FullName": "owner/repo", "Stars": 10}]`,
+			want: &snapshotFile{SchemaVersion: 1, Repositories: []*domain.Repository{{FullName: "owner/repo", Stars: 10}}},
+		},
+		{
+			name: "envelope",
+			data: `{"schema_version": 2, "fetcher": "graphql", "tool_version": "v1.0.0", "repositories": [{"FullName": "owner/repo", "Stars": 10, "Status": "ok"}]}`,
+			want: &snapshotFile{SchemaVersion: 2, Fetcher: "graphql", ToolVersion: "v1.0.0",
+				Repositories: []*domain.Repository{{FullName: "owner/repo", Stars: 10, Status: domain.StatusOK}}},
+		},
+		{name: "newer schema", data: `{"schema_version": 99, "repositories": []}`, wantErr: "newer version"},
+		{name: "missing schema version", data: `{"repositories": []}`, wantErr: "missing schema version"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeSnapshotFile([]byte(tt.data))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFileStorer_SnapshotMetadata(t *testing.T) {
+	storer, cfg := setupTestStorer(t)
+	cfg.Fetcher = "graphql"
+	date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	fetchedAt := date.Add(8 * time.Hour)
+
+	readFile := func() *snapshotFile {
+		data, err := os.ReadFile(storer.getDailyDataPath(date))
+		require.NoError(t, err)
+		file, err := decodeSnapshotFile(data)
+		require.NoError(t, err)
+		return file
+	}
+
+	repo := &domain.Repository{FullName: "owner/repo", Stars: 10, Status: domain.StatusOK, FetchedAt: fetchedAt}
+	require.NoError(t, storer.Merge(date, []*domain.Repository{repo}))
+	file := readFile()
+	assert.Equal(t, snapshotSchemaVersion, file.SchemaVersion)
+	assert.Equal(t, "graphql", file.Fetcher)
+	assert.NotEmpty(t, file.ToolVersion)
+	assert.Equal(t, fetchedAt, file.FetchedAt)
+
+	// Rewriting the snapshot, as migrations do, keeps the metadata of the fetch.
+	cfg.Fetcher = "rest"
+	repo.ID = 42
+	require.NoError(t, storer.Save(date, []*domain.Repository{repo}))
+	assert.Equal(t, "graphql", readFile().Fetcher)
+}
+
+func TestFileStorer_UpgradeSchema(t *testing.T) {
+	storer, cfg := setupTestStorer(t)
+	legacy := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	current := legacy.AddDate(0, 0, 1)
+
+	require.NoError(t, os.MkdirAll(cfg.DataDirPath, 0755))
+	require.NoError(t, os.WriteFile(storer.getDailyDataPath(legacy), []byte(`[{"FullName": "owner/repo", "Stars": 10}]`), 0644))
+	require.NoError(t, storer.Save(current, []*domain.Repository{{FullName: "owner/repo", Stars: 11}}))
+
+	upgraded, err := storer.UpgradeSchema()
+	require.NoError(t, err)
+	assert.Equal(t, 1, upgraded)
+
+	data, err := os.ReadFile(storer.getDailyDataPath(legacy))
+	require.NoError(t, err)
+	file, err := decodeSnapshotFile(data)
+	require.NoError(t, err)
+	assert.Equal(t, snapshotSchemaVersion, file.SchemaVersion)
+	assert.Equal(t, []*domain.Repository{{FullName: "owner/repo", Stars: 10}}, file.Repositories)
+
+	upgraded, err = storer.UpgradeSchema()
+	require.NoError(t, err)
+	assert.Zero(t, upgraded)
+}
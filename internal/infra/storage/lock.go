@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// lockFileName is the name of the file in the data directory that is locked to serialize the
+// reads and writes of FileStorer across processes, such as an update and a generate run at the
+// same time or two overlapping cron jobs.
+const lockFileName = ".lock"
+
+// getLockPath returns the path to the lock file.
+func (fs *FileStorer) getLockPath() string {
+	return filepath.Join(fs.cfg.DataDirPath, lockFileName)
+}
+
+// lock locks the data directory, exclusively for writers and shared for readers, waiting for
+// other processes holding a conflicting lock. It returns the function that releases the lock.
+// Readers don't lock a data directory that doesn't exist yet, as there is nothing to read.
+func (fs *FileStorer) lock(exclusive bool) (func(), error) {
+	if !exclusive {
+		if _, err := os.Stat(fs.cfg.DataDirPath); os.IsNotExist(err) {
+			return func() {}, nil
+		}
+	}
+	if err := os.MkdirAll(fs.cfg.DataDirPath, 0755); err != nil {
+		fs.logger.Error("Failed to create data directory", "path", fs.cfg.DataDirPath, "error", err)
+		return nil, fmt.Errorf("could not create data directory '%s': %w", fs.cfg.DataDirPath, err)
+	}
+
+	path := fs.getLockPath()
+	file, err := openLockFile(path, exclusive)
+	if err != nil {
+		// Read-only commands still work on a data directory they can't write to, such as a
+		// read-only mount, at the cost of not being serialized with writers.
+		if !exclusive && isReadOnlyErr(err) {
+			fs.logger.Debug("Reading data directory without a lock, as the lock file can't be opened", "path", path, "error", err)
+			return func() {}, nil
+		}
+		fs.logger.Error("Failed to open lock file", "path", path, "error", err)
+		return nil, fmt.Errorf("could not open lock file '%s': %w", path, err)
+	}
+	wait := func() {
+		fs.logger.Info("Waiting for another process to release the data directory", "path", path)
+	}
+	if err := lockFile(file, exclusive, wait); err != nil {
+		file.Close()
+		fs.logger.Error("Failed to lock data directory", "path", path, "error", err)
+		return nil, fmt.Errorf("could not lock '%s': %w", path, err)
+	}
+	return func() {
+		unlockFile(file)
+		file.Close()
+	}, nil
+}
+
+// openLockFile opens the lock file at path, creating it if needed. Shared locks only need read
+// access, so an existing lock file is opened read-only for them.
+func openLockFile(path string, exclusive bool) (*os.File, error) {
+	if !exclusive {
+		file, err := os.Open(path)
+		if !os.IsNotExist(err) {
+			return file, err
+		}
+	}
+	return os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+}
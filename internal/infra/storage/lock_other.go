@@ -0,0 +1,24 @@
+//go:build !unix && !windows
+
+package storage
+
+import (
+	"errors"
+	"os"
+)
+
+// lockFile does nothing on platforms without file locking, such as js/wasm. Writes are still
+// atomic, but concurrent processes aren't serialized.
+func lockFile(file *os.File, exclusive bool, wait func()) error {
+	return nil
+}
+
+// unlockFile does nothing, as lockFile takes no lock.
+func unlockFile(file *os.File) error {
+	return nil
+}
+
+// isReadOnlyErr reports whether err means that the lock file can't be written or created.
+func isReadOnlyErr(err error) bool {
+	return errors.Is(err, os.ErrPermission)
+}
@@ -0,0 +1,44 @@
+//go:build unix
+
+package storage
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockFile locks file with flock, calling wait before blocking if another process holds a
+// conflicting lock.
+func lockFile(file *os.File, exclusive bool, wait func()) error {
+	how := syscall.LOCK_SH
+	if exclusive {
+		how = syscall.LOCK_EX
+	}
+	err := flock(file, how|syscall.LOCK_NB)
+	if !errors.Is(err, syscall.EWOULDBLOCK) {
+		return err
+	}
+	wait()
+	return flock(file, how)
+}
+
+// unlockFile releases the lock taken by lockFile.
+func unlockFile(file *os.File) error {
+	return flock(file, syscall.LOCK_UN)
+}
+
+// flock calls flock(2), retrying when it is interrupted by a signal.
+func flock(file *os.File, how int) error {
+	for {
+		err := syscall.Flock(int(file.Fd()), how)
+		if !errors.Is(err, syscall.EINTR) {
+			return err
+		}
+	}
+}
+
+// isReadOnlyErr reports whether err means that the lock file can't be written or created.
+func isReadOnlyErr(err error) bool {
+	return errors.Is(err, os.ErrPermission) || errors.Is(err, syscall.EROFS)
+}
@@ -0,0 +1,40 @@
+//go:build windows
+
+package storage
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockFile locks file with LockFileEx, calling wait before blocking if another process holds a
+// conflicting lock.
+func lockFile(file *os.File, exclusive bool, wait func()) error {
+	var flags uint32
+	if exclusive {
+		flags = windows.LOCKFILE_EXCLUSIVE_LOCK
+	}
+	err := lockFileEx(file, flags|windows.LOCKFILE_FAIL_IMMEDIATELY)
+	if !errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return err
+	}
+	wait()
+	return lockFileEx(file, flags)
+}
+
+// unlockFile releases the lock taken by lockFile.
+func unlockFile(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, new(windows.Overlapped))
+}
+
+// lockFileEx locks the first byte of file, which is enough as every process locks the same range.
+func lockFileEx(file *os.File, flags uint32) error {
+	return windows.LockFileEx(windows.Handle(file.Fd()), flags, 0, 1, 0, new(windows.Overlapped))
+}
+
+// isReadOnlyErr reports whether err means that the lock file can't be written or created.
+func isReadOnlyErr(err error) bool {
+	return errors.Is(err, os.ErrPermission) || errors.Is(err, windows.ERROR_WRITE_PROTECT)
+}
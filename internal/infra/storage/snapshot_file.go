@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+// snapshotSchemaVersion is the version of the format of the daily data files written by FileStorer.
+//
+//   - 1: a bare JSON array of repositories. Files written before versioning have this format.
+//   - 2: an object wrapping the repositories with the metadata of the snapshot.
+const snapshotSchemaVersion = 2
+
+// errNewerSchema is returned for data files written by a newer version of go-trendboard.
+var errNewerSchema = errors.New("data file was written by a newer version of go-trendboard")
+
+// snapshotFile is the content of a daily data file.
+type snapshotFile struct {
+	// SchemaVersion is the version of the format the file was written in.
+	SchemaVersion int `json:"schema_version"`
+	// FetchedAt is when the most recent star count of the snapshot was fetched.
+	FetchedAt time.Time `json:"fetched_at,omitzero"`
+	// Fetcher is the GitHub API the snapshot was fetched with, "rest" or "graphql".
+	// It is empty for snapshots migrated from the legacy format.
+	Fetcher string `json:"fetcher,omitempty"`
+	// ToolVersion is the version of go-trendboard that last fetched into the snapshot.
+	ToolVersion string `json:"tool_version,omitempty"`
+	// Repositories are the repositories of the snapshot, each with the outcome of its fetch.
+	Repositories []*domain.Repository `json:"repositories"`
+}
+
+// newSnapshotFile creates the content of a data file in the current format for repos,
+// with the given metadata.
+func newSnapshotFile(repos []*domain.Repository, fetcher, toolVersion string) *snapshotFile {
+	file := &snapshotFile{
+		SchemaVersion: snapshotSchemaVersion,
+		Fetcher:       fetcher,
+		ToolVersion:   toolVersion,
+		Repositories:  repos,
+	}
+	for _, repo := range repos {
+		if repo.FetchedAt.After(file.FetchedAt) {
+			file.FetchedAt = repo.FetchedAt
+		}
+	}
+	return file
+}
+
+// decodeSnapshotFile decodes a data file in the current or the legacy format.
+func decodeSnapshotFile(data []byte) (*snapshotFile, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var repos []*domain.Repository
+		if err := json.Unmarshal(data, &repos); err != nil {
+			return nil, err
+		}
+		return &snapshotFile{SchemaVersion: 1, Repositories: repos}, nil
+	}
+
+	var file snapshotFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if file.SchemaVersion < 2 {
+		return nil, errors.New("missing schema version")
+	}
+	if file.SchemaVersion > snapshotSchemaVersion {
+		return nil, fmt.Errorf("%w: schema version %d, supported up to %d", errNewerSchema, file.SchemaVersion, snapshotSchemaVersion)
+	}
+	if file.Repositories == nil {
+		file.Repositories = []*domain.Repository{}
+	}
+	return &file, nil
+}
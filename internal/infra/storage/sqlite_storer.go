@@ -0,0 +1,483 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "modernc.org/sqlite" // Pure-Go driver, so that builds stay static.
+
+	"github.com/yourname/go-trendboard/internal/config"
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+// sqliteSchema creates the tables of the SQLite backend. Repositories are stored once and
+// referenced by the metrics of each snapshot; a repository's GitHub ID is kept once known.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS repos (
+	pk        INTEGER PRIMARY KEY AUTOINCREMENT,
+	full_name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+	github_id INTEGER NOT NULL DEFAULT 0
+);
+CREATE TABLE IF NOT EXISTS snapshots (
+	date TEXT PRIMARY KEY
+);
+CREATE TABLE IF NOT EXISTS metrics (
+	snapshot_date TEXT NOT NULL REFERENCES snapshots(date) ON DELETE CASCADE,
+	repo_pk       INTEGER NOT NULL REFERENCES repos(pk),
+	position      INTEGER NOT NULL,
+	stars         INTEGER NOT NULL,
+	status        TEXT NOT NULL DEFAULT '',
+	stale         INTEGER NOT NULL DEFAULT 0,
+	renamed_from  TEXT NOT NULL DEFAULT '',
+	PRIMARY KEY (snapshot_date, repo_pk)
+);
+CREATE INDEX IF NOT EXISTS metrics_repo ON metrics (repo_pk, snapshot_date);
+CREATE TABLE IF NOT EXISTS renames (
+	from_name   TEXT PRIMARY KEY COLLATE NOCASE,
+	to_name     TEXT NOT NULL,
+	github_id   INTEGER NOT NULL DEFAULT 0,
+	detected_at TEXT NOT NULL
+);
+`
+
+// sqliteMigrations upgrade the schema created by sqliteSchema in order. The schema version of a
+// database, kept in its user_version, is the number of migrations applied to it.
+var sqliteMigrations = []string{
+	// 1: fetch time and intra-day samples of the metrics.
+	`ALTER TABLE metrics ADD COLUMN fetched_at TEXT NOT NULL DEFAULT '';
+	ALTER TABLE metrics ADD COLUMN samples TEXT NOT NULL DEFAULT '';`,
+}
+
+// repositoryColumns are the columns read by scanRepository, from the metrics (m) joined with the repos (r).
+const repositoryColumns = `m.snapshot_date, r.github_id, r.full_name, m.stars, m.status, m.stale, m.renamed_from, m.fetched_at, m.samples`
+
+// querier runs queries on the database or in a transaction.
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// SQLiteStorer implements the Storer interface with a SQLite database.
+// The list of repositories to track stays in repos.json, which users edit by hand.
+type SQLiteStorer struct {
+	db      *sql.DB
+	targets *FileStorer
+	logger  *slog.Logger
+}
+
+// NewSQLiteStorer opens the SQLite database at the configured path, creating it and its schema if needed.
+func NewSQLiteStorer(cfg *config.Config, logger *slog.Logger) (*SQLiteStorer, error) {
+	logger = logger.With("component", "sqlite_storer")
+	path := cfg.SQLitePath
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		logger.Error("Failed to create database directory", "path", path, "error", err)
+		return nil, fmt.Errorf("could not create directory for database '%s': %w", path, err)
+	}
+	// Transactions begin IMMEDIATE, taking the write lock up front: a deferred transaction that
+	// reads before writing fails with SQLITE_BUSY instead of waiting when another process writes.
+	db, err := sql.Open("sqlite", path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_txlock=immediate")
+	if err != nil {
+		logger.Error("Failed to open database", "path", path, "error", err)
+		return nil, fmt.Errorf("could not open database '%s': %w", path, err)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		logger.Error("Failed to create database schema", "path", path, "error", err)
+		return nil, fmt.Errorf("could not create schema of database '%s': %w", path, err)
+	}
+
+	s := &SQLiteStorer{
+		db:      db,
+		targets: NewFileStorer(cfg, logger),
+		logger:  logger,
+	}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		logger.Error("Failed to upgrade database schema", "path", path, "error", err)
+		return nil, fmt.Errorf("could not upgrade schema of database '%s': %w", path, err)
+	}
+	return s, nil
+}
+
+// migrate applies the migrations of the schema the database is missing.
+func (s *SQLiteStorer) migrate() error {
+	var version int
+	if err := s.db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+	for i := version; i < len(sqliteMigrations); i++ {
+		err := s.inTx(func(tx *sql.Tx) error {
+			if _, err := tx.Exec(sqliteMigrations[i]); err != nil {
+				return err
+			}
+			_, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, i+1))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		s.logger.Info("Upgraded database schema", "version", i+1)
+	}
+	return nil
+}
+
+// Close closes the database.
+func (s *SQLiteStorer) Close() error {
+	return s.db.Close()
+}
+
+// Save replaces the snapshot of a specific date with the given repositories.
+func (s *SQLiteStorer) Save(date time.Time, repos []*domain.Repository) error {
+	day := date.Format(dateLayout)
+	s.logger.Debug("Saving data", "date", day)
+
+	if err := s.inTx(func(tx *sql.Tx) error { return saveDay(tx, day, repos) }); err != nil {
+		s.logger.Error("Failed to save data", "date", day, "error", err)
+		return fmt.Errorf("could not save data of %s: %w", day, err)
+	}
+
+	s.logger.Info("Successfully saved data", "date", day, "count", len(repos))
+	return nil
+}
+
+// Merge merges repos into the snapshot of a specific date with domain.MergeSnapshot,
+// reading and replacing the snapshot in a single transaction.
+func (s *SQLiteStorer) Merge(date time.Time, repos []*domain.Repository) error {
+	day := date.Format(dateLayout)
+	s.logger.Debug("Merging data", "date", day)
+
+	err := s.inTx(func(tx *sql.Tx) error {
+		saved, _, err := loadDay(tx, day)
+		if err != nil {
+			return err
+		}
+		return saveDay(tx, day, domain.MergeSnapshot(saved, repos))
+	})
+	if err != nil {
+		s.logger.Error("Failed to merge data", "date", day, "error", err)
+		return fmt.Errorf("could not merge data of %s: %w", day, err)
+	}
+
+	s.logger.Info("Successfully merged data", "date", day, "count", len(repos))
+	return nil
+}
+
+// saveDay replaces the snapshot of day with repos in tx.
+func saveDay(tx *sql.Tx, day string, repos []*domain.Repository) error {
+	if _, err := tx.Exec(`INSERT OR IGNORE INTO snapshots (date) VALUES (?)`, day); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM metrics WHERE snapshot_date = ?`, day); err != nil {
+		return err
+	}
+	for i, repo := range repos {
+		var pk int64
+		err := tx.QueryRow(`
+			INSERT INTO repos (full_name, github_id) VALUES (?, ?)
+			ON CONFLICT (full_name) DO UPDATE SET
+				full_name = excluded.full_name,
+				github_id = CASE WHEN excluded.github_id != 0 THEN excluded.github_id ELSE repos.github_id END
+			RETURNING pk`, repo.FullName, repo.ID).Scan(&pk)
+		if err != nil {
+			return err
+		}
+		var fetchedAt, samples string
+		if !repo.FetchedAt.IsZero() {
+			fetchedAt = repo.FetchedAt.UTC().Format(time.RFC3339Nano)
+		}
+		if len(repo.Samples) > 0 {
+			data, err := json.Marshal(repo.Samples)
+			if err != nil {
+				return err
+			}
+			samples = string(data)
+		}
+		_, err = tx.Exec(`
+			INSERT OR REPLACE INTO metrics (snapshot_date, repo_pk, position, stars, status, stale, renamed_from, fetched_at, samples)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`, day, pk, i, repo.Stars, string(repo.Status), repo.Stale, repo.RenamedFrom, fetchedAt, samples)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load loads the snapshot of a specific date.
+func (s *SQLiteStorer) Load(date time.Time) ([]*domain.Repository, error) {
+	day := date.Format(dateLayout)
+	s.logger.Debug("Loading data", "date", day)
+
+	repos, exists, err := loadDay(s.db, day)
+	if err != nil {
+		s.logger.Error("Failed to load data", "date", day, "error", err)
+		return nil, fmt.Errorf("could not load data of %s: %w", day, err)
+	}
+	if !exists {
+		s.logger.Warn("Data not found", "date", day)
+		return nil, ErrDataNotFound
+	}
+
+	s.logger.Debug("Successfully loaded data", "date", day)
+	return repos, nil
+}
+
+// loadDay loads the snapshot of day with q, reporting whether it exists.
+func loadDay(q querier, day string) ([]*domain.Repository, bool, error) {
+	var exists bool
+	if err := q.QueryRow(`SELECT EXISTS (SELECT 1 FROM snapshots WHERE date = ?)`, day).Scan(&exists); err != nil {
+		return nil, false, err
+	}
+	if !exists {
+		return nil, false, nil
+	}
+
+	rows, err := q.Query(`
+		SELECT `+repositoryColumns+`
+		FROM metrics m JOIN repos r ON r.pk = m.repo_pk
+		WHERE m.snapshot_date = ?
+		ORDER BY m.position`, day)
+	if err != nil {
+		return nil, false, err
+	}
+	defer rows.Close()
+
+	repos := []*domain.Repository{}
+	for rows.Next() {
+		repo, err := scanRepository(rows, new(string))
+		if err != nil {
+			return nil, false, err
+		}
+		repos = append(repos, repo)
+	}
+	return repos, true, rows.Err()
+}
+
+// LoadRange loads the snapshots of the dates from from to to, both inclusive, with a single query.
+func (s *SQLiteStorer) LoadRange(from, to time.Time) ([]domain.Snapshot, error) {
+	first, last := from.Format(dateLayout), to.Format(dateLayout)
+
+	dates, err := s.ListDates()
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []domain.Snapshot
+	byDay := make(map[string]int)
+	for _, date := range dates {
+		if day := date.Format(dateLayout); day >= first && day <= last {
+			byDay[day] = len(snapshots)
+			snapshots = append(snapshots, domain.Snapshot{Date: date, Repositories: []*domain.Repository{}})
+		}
+	}
+
+	rows, err := s.db.Query(`
+		SELECT `+repositoryColumns+`
+		FROM metrics m JOIN repos r ON r.pk = m.repo_pk
+		WHERE m.snapshot_date BETWEEN ? AND ?
+		ORDER BY m.snapshot_date, m.position`, first, last)
+	if err != nil {
+		s.logger.Error("Failed to query data", "from", first, "to", last, "error", err)
+		return nil, fmt.Errorf("could not query data from %s to %s: %w", first, last, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var day string
+		repo, err := scanRepository(rows, &day)
+		if err != nil {
+			return nil, fmt.Errorf("could not read data from %s to %s: %w", first, last, err)
+		}
+		if i, ok := byDay[day]; ok {
+			repo.Samples = nil // History queries only use the value of the day, as with the file backend.
+			snapshots[i].Repositories = append(snapshots[i].Repositories, repo)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		s.logger.Error("Failed to read data", "from", first, "to", last, "error", err)
+		return nil, fmt.Errorf("could not read data from %s to %s: %w", first, last, err)
+	}
+	return snapshots, nil
+}
+
+// LoadSeries loads the history of a single repository with a single query.
+func (s *SQLiteStorer) LoadSeries(repoName string, from, to time.Time) ([]domain.SeriesPoint, error) {
+	first, last := from.Format(dateLayout), to.Format(dateLayout)
+
+	// full_name is compared ignoring case, as declared by its collation.
+	rows, err := s.db.Query(`
+		SELECT `+repositoryColumns+`
+		FROM metrics m JOIN repos r ON r.pk = m.repo_pk
+		WHERE r.full_name = ? AND m.snapshot_date BETWEEN ? AND ?
+		ORDER BY m.snapshot_date`, repoName, first, last)
+	if err != nil {
+		s.logger.Error("Failed to query series", "repo", repoName, "error", err)
+		return nil, fmt.Errorf("could not query series of '%s': %w", repoName, err)
+	}
+	defer rows.Close()
+
+	var series []domain.SeriesPoint
+	for rows.Next() {
+		var day string
+		repo, err := scanRepository(rows, &day)
+		if err != nil {
+			return nil, fmt.Errorf("could not read series of '%s': %w", repoName, err)
+		}
+		date, err := time.Parse(dateLayout, day)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot date '%s': %w", day, err)
+		}
+		repo.Samples = nil
+		series = append(series, domain.SeriesPoint{Date: date, Repository: repo})
+	}
+	return series, rows.Err()
+}
+
+// LoadLatest loads the most recent snapshot.
+func (s *SQLiteStorer) LoadLatest() (*domain.Snapshot, error) {
+	var day sql.NullString
+	if err := s.db.QueryRow(`SELECT MAX(date) FROM snapshots`).Scan(&day); err != nil {
+		s.logger.Error("Failed to query latest snapshot", "error", err)
+		return nil, fmt.Errorf("could not query latest snapshot: %w", err)
+	}
+	if !day.Valid {
+		return nil, ErrDataNotFound
+	}
+	date, err := time.Parse(dateLayout, day.String)
+	if err != nil {
+		return nil, fmt.Errorf("invalid snapshot date '%s': %w", day.String, err)
+	}
+	repos, err := s.Load(date)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Snapshot{Date: date, Repositories: repos}, nil
+}
+
+// scanRepository reads a repository from the repositoryColumns of a row,
+// starting with the snapshot date, which is stored into day.
+func scanRepository(rows *sql.Rows, day *string) (*domain.Repository, error) {
+	var (
+		repo                       domain.Repository
+		status, fetchedAt, samples string
+	)
+	if err := rows.Scan(day, &repo.ID, &repo.FullName, &repo.Stars, &status, &repo.Stale, &repo.RenamedFrom, &fetchedAt, &samples); err != nil {
+		return nil, err
+	}
+	repo.Status = domain.FetchStatus(status)
+	if fetchedAt != "" {
+		t, err := time.Parse(time.RFC3339Nano, fetchedAt)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fetch time of '%s': %w", repo.FullName, err)
+		}
+		repo.FetchedAt = t
+	}
+	if samples != "" {
+		if err := json.Unmarshal([]byte(samples), &repo.Samples); err != nil {
+			return nil, fmt.Errorf("invalid samples of '%s': %w", repo.FullName, err)
+		}
+	}
+	return &repo, nil
+}
+
+// ListDates returns the dates that have a snapshot, in ascending order.
+func (s *SQLiteStorer) ListDates() ([]time.Time, error) {
+	rows, err := s.db.Query(`SELECT date FROM snapshots ORDER BY date`)
+	if err != nil {
+		s.logger.Error("Failed to list snapshots", "error", err)
+		return nil, fmt.Errorf("could not list snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var dates []time.Time
+	for rows.Next() {
+		var day string
+		if err := rows.Scan(&day); err != nil {
+			return nil, fmt.Errorf("could not list snapshots: %w", err)
+		}
+		date, err := time.Parse(dateLayout, day)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot date '%s': %w", day, err)
+		}
+		dates = append(dates, date)
+	}
+	return dates, rows.Err()
+}
+
+// LoadTargetRepos loads the list of target repositories from repos.json.
+func (s *SQLiteStorer) LoadTargetRepos() ([]domain.TargetRepo, error) {
+	return s.targets.LoadTargetRepos()
+}
+
+// SaveTargetRepos saves the list of target repositories to repos.json.
+func (s *SQLiteStorer) SaveTargetRepos(repos []domain.TargetRepo) error {
+	return s.targets.SaveTargetRepos(repos)
+}
+
+// LoadRenames loads the recorded repository renames, in the order they were saved.
+func (s *SQLiteStorer) LoadRenames() ([]domain.Rename, error) {
+	rows, err := s.db.Query(`SELECT from_name, to_name, github_id, detected_at FROM renames ORDER BY rowid`)
+	if err != nil {
+		s.logger.Error("Failed to query renames", "error", err)
+		return nil, fmt.Errorf("could not query renames: %w", err)
+	}
+	defer rows.Close()
+
+	var renames []domain.Rename
+	for rows.Next() {
+		var (
+			rename     domain.Rename
+			detectedAt string
+		)
+		if err := rows.Scan(&rename.From, &rename.To, &rename.ID, &detectedAt); err != nil {
+			return nil, fmt.Errorf("could not read renames: %w", err)
+		}
+		if rename.DetectedAt, err = time.Parse(time.RFC3339Nano, detectedAt); err != nil {
+			return nil, fmt.Errorf("invalid detection time of rename from '%s': %w", rename.From, err)
+		}
+		renames = append(renames, rename)
+	}
+	return renames, rows.Err()
+}
+
+// SaveRenames replaces the recorded repository renames.
+func (s *SQLiteStorer) SaveRenames(renames []domain.Rename) error {
+	err := s.inTx(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`DELETE FROM renames`); err != nil {
+			return err
+		}
+		for _, rename := range renames {
+			_, err := tx.Exec(`INSERT INTO renames (from_name, to_name, github_id, detected_at) VALUES (?, ?, ?, ?)`,
+				rename.From, rename.To, rename.ID, rename.DetectedAt.UTC().Format(time.RFC3339Nano))
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to save renames", "error", err)
+		return fmt.Errorf("could not save renames: %w", err)
+	}
+
+	s.logger.Info("Successfully saved renames", "count", len(renames))
+	return nil
+}
+
+// inTx runs fn in a transaction, committing it if fn succeeds and rolling it back otherwise.
+func (s *SQLiteStorer) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(context.Background(), nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return tx.Commit()
+}
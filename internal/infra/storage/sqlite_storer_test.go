@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourname/go-trendboard/internal/config"
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+// setupTestSQLiteStorer creates a SQLiteStorer instance for testing, using a temporary directory.
+func setupTestSQLiteStorer(t *testing.T) (*SQLiteStorer, *config.Config) {
+	t.Helper()
+	tempDir := t.TempDir()
+
+	cfg := &config.Config{
+		DataDirPath:   filepath.Join(tempDir, "data"),
+		ReposFilePath: filepath.Join(tempDir, "repos.json"),
+		SQLitePath:    filepath.Join(tempDir, "data", "trendboard.db"),
+	}
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil)) // Discard logs
+
+	storer, err := NewSQLiteStorer(cfg, logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { storer.Close() })
+	return storer, cfg
+}
+
+func TestSQLiteStorer_SaveAndLoad(t *testing.T) {
+	storer, _ := setupTestSQLiteStorer(t)
+	date := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	repos := []*domain.Repository{
+		{ID: 2, FullName: "owner/repo2", Stars: 200, Status: domain.StatusOK},
+		{ID: 1, FullName: "owner/repo1", Stars: 100, Status: domain.StatusRenamed, RenamedFrom: "owner/old"},
+		{FullName: "owner/failed", Stars: 50, Status: domain.StatusFailed, Stale: true},
+	}
+	require.NoError(t, storer.Save(date, repos))
+
+	loaded, err := storer.Load(date)
+	require.NoError(t, err)
+	assert.Equal(t, repos, loaded)
+
+	// Saving again replaces the snapshot.
+	require.NoError(t, storer.Save(date, repos[:1]))
+	loaded, err = storer.Load(date)
+	require.NoError(t, err)
+	assert.Equal(t, repos[:1], loaded)
+
+	// An empty snapshot is not missing.
+	empty := date.AddDate(0, 0, 1)
+	require.NoError(t, storer.Save(empty, nil))
+	loaded, err = storer.Load(empty)
+	require.NoError(t, err)
+	assert.Empty(t, loaded)
+}
+
+func TestSQLiteStorer_Load_NotFound(t *testing.T) {
+	storer, _ := setupTestSQLiteStorer(t)
+	_, err := storer.Load(time.Now())
+	assert.ErrorIs(t, err, ErrDataNotFound)
+}
+
+func TestSQLiteStorer_ListDates(t *testing.T) {
+	storer, _ := setupTestSQLiteStorer(t)
+
+	later := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+	earlier := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, storer.Save(later, nil))
+	require.NoError(t, storer.Save(earlier, nil))
+
+	dates, err := storer.ListDates()
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{earlier, later}, dates)
+}
+
+func TestSQLiteStorer_SaveAndLoadRenames(t *testing.T) {
+	storer, _ := setupTestSQLiteStorer(t)
+
+	renames, err := storer.LoadRenames()
+	require.NoError(t, err)
+	assert.Empty(t, renames)
+
+	detectedAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	expected := []domain.Rename{
+		{From: "b/old", To: "b/new", DetectedAt: detectedAt},
+		{From: "a/old", To: "a/new", ID: 42, DetectedAt: detectedAt},
+	}
+	require.NoError(t, storer.SaveRenames(expected))
+
+	renames, err = storer.LoadRenames()
+	require.NoError(t, err)
+	assert.Equal(t, expected, renames)
+}
+
+func TestSQLiteStorer_TargetReposStayInFile(t *testing.T) {
+	storer, cfg := setupTestSQLiteStorer(t)
+	targets := []domain.TargetRepo{{Name: "gin-gonic/gin"}}
+
+	require.NoError(t, storer.SaveTargetRepos(targets))
+	assert.FileExists(t, cfg.ReposFilePath)
+
+	loaded, err := storer.LoadTargetRepos()
+	require.NoError(t, err)
+	assert.Equal(t, targets, loaded)
+}
+
+// TestSQLiteStorer_ConcurrentMerges checks that updates merging into the same day from separate
+// processes wait for each other instead of failing with SQLITE_BUSY or losing values.
+func TestSQLiteStorer_ConcurrentMerges(t *testing.T) {
+	_, cfg := setupTestSQLiteStorer(t)
+	date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+
+	const writers = 8
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+	for i := range writers {
+		storer, err := NewSQLiteStorer(cfg, logger)
+		require.NoError(t, err)
+		t.Cleanup(func() { storer.Close() })
+		wg.Go(func() {
+			repo := &domain.Repository{ID: int64(i + 1), FullName: fmt.Sprintf("owner/repo%d", i), Stars: i, Status: domain.StatusOK}
+			errs[i] = storer.Merge(date, []*domain.Repository{repo})
+		})
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	storer, err := NewSQLiteStorer(cfg, logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { storer.Close() })
+	repos, err := storer.Load(date)
+	require.NoError(t, err)
+	assert.Len(t, repos, writers)
+}
+
+func TestNewStorer(t *testing.T) {
+	tempDir := t.TempDir()
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+
+	storer, err := NewStorer(&config.Config{StorageBackend: "file"}, logger)
+	require.NoError(t, err)
+	assert.IsType(t, &FileStorer{}, storer)
+
+	storer, err = NewStorer(&config.Config{StorageBackend: "sqlite", SQLitePath: filepath.Join(tempDir, "trendboard.db")}, logger)
+	require.NoError(t, err)
+	assert.IsType(t, &SQLiteStorer{}, storer)
+	require.NoError(t, storer.(*SQLiteStorer).Close())
+
+	_, err = NewStorer(&config.Config{StorageBackend: "postgres"}, logger)
+	require.Error(t, err)
+}
+
+func TestSQLiteStorer_UpgradesSchema(t *testing.T) {
+	storer, cfg := setupTestSQLiteStorer(t)
+	require.NoError(t, storer.Close())
+
+	// Recreate the database as the first version of the schema left it.
+	require.NoError(t, os.Remove(cfg.SQLitePath))
+	db, err := sql.Open("sqlite", cfg.SQLitePath)
+	require.NoError(t, err)
+	_, err = db.Exec(sqliteSchema + `
+		INSERT INTO repos (full_name, github_id) VALUES ('owner/repo', 1);
+		INSERT INTO snapshots (date) VALUES ('2025-01-01');
+		INSERT INTO metrics (snapshot_date, repo_pk, position, stars, status) VALUES ('2025-01-01', 1, 0, 10, 'ok');`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+	storer, err = NewSQLiteStorer(cfg, logger)
+	require.NoError(t, err)
+	defer storer.Close()
+
+	repos, err := storer.Load(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Repository{{ID: 1, FullName: "owner/repo", Stars: 10, Status: domain.StatusOK}}, repos)
+
+	var version int
+	require.NoError(t, storer.db.QueryRow(`PRAGMA user_version`).Scan(&version))
+	assert.Equal(t, len(sqliteMigrations), version)
+}
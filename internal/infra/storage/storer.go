@@ -2,11 +2,17 @@ package storage
 
 import (
 	"errors"
+	"fmt"
+	"log/slog"
 	"time"
 
+	"github.com/yourname/go-trendboard/internal/config"
 	"github.com/yourname/go-trendboard/internal/domain"
 )
 
+// dateLayout is the format of snapshot dates, as used in the names of the daily data files.
+const dateLayout = "2006-01-02"
+
 var (
 	// ErrDataNotFound is returned when trend data for a specific date is not found.
 	ErrDataNotFound = errors.New("trend data not found for the specified date")
@@ -19,13 +25,70 @@ type Storer interface {
 	// Save saves the list of repositories for a specific date.
 	Save(date time.Time, repos []*domain.Repository) error
 
+	// Merge merges the repositories fetched by an update into the saved snapshot of a specific date,
+	// as described by domain.MergeSnapshot, so that several updates a day add up instead of
+	// overwriting each other. It saves the repositories as they are if the date has no snapshot yet.
+	Merge(date time.Time, repos []*domain.Repository) error
+
 	// Load loads the list of repositories for a specific date.
 	Load(date time.Time) ([]*domain.Repository, error)
 
-	// LoadTargetRepos loads the list of target repository names from the configuration.
-	LoadTargetRepos() ([]string, error)
+	// LoadRange loads the snapshots of the dates from from to to, both inclusive, in ascending order.
+	// Dates without saved data are skipped. Repositories hold the value of the day, without their
+	// intra-day samples, which only Load returns.
+	LoadRange(from, to time.Time) ([]domain.Snapshot, error)
+
+	// LoadSeries loads the history of a single repository, matched by name ignoring case,
+	// over the dates from from to to, both inclusive, in ascending order, like LoadRange.
+	LoadSeries(repoName string, from, to time.Time) ([]domain.SeriesPoint, error)
+
+	// LoadLatest loads the most recent snapshot. It returns ErrDataNotFound if no data was saved.
+	LoadLatest() (*domain.Snapshot, error)
+
+	// ListDates returns the dates that have saved data, in ascending order.
+	ListDates() ([]time.Time, error)
+
+	// LoadTargetRepos loads the list of target repositories from the configuration.
+	LoadTargetRepos() ([]domain.TargetRepo, error)
+
+	// SaveTargetRepos saves the list of target repositories to the configuration.
+	// This is used by the 'init' and 'repo' commands.
+	SaveTargetRepos(repos []domain.TargetRepo) error
+
+	// LoadRenames loads the recorded repository renames.
+	// It returns no renames and no error when none have been recorded yet.
+	LoadRenames() ([]domain.Rename, error)
+
+	// SaveRenames saves the recorded repository renames.
+	SaveRenames(renames []domain.Rename) error
+}
+
+// SchemaUpgrader is implemented by storers that can rewrite data saved in older formats on request.
+// Storers that upgrade their data on their own, such as the SQLite database, don't implement it.
+type SchemaUpgrader interface {
+	// UpgradeSchema rewrites the snapshots saved in an older format in the current one.
+	// It returns the number of snapshots that were upgraded.
+	UpgradeSchema() (int, error)
+}
+
+// Compactor is implemented by storers that can roll old snapshots into compressed archives,
+// which they keep reading transparently.
+type Compactor interface {
+	// Compact moves the snapshots of the dates in archive into compressed archives and deletes
+	// the snapshots of the dates in drop. It returns the number of snapshots that were archived,
+	// not counting those archived before, and of those that were deleted.
+	Compact(archive, drop []time.Time) (archived, dropped int, err error)
+}
 
-	// SaveTargetRepos saves the list of target repository names to the configuration.
-	// This is used for the 'init' command.
-	SaveTargetRepos(repos []string) error
+// NewStorer is a factory function that returns the storer of the configured storage backend.
+// Storers that hold resources, such as the SQLite database, implement io.Closer.
+func NewStorer(cfg *config.Config, logger *slog.Logger) (Storer, error) {
+	switch cfg.StorageBackend {
+	case "", "file":
+		return NewFileStorer(cfg, logger), nil
+	case "sqlite":
+		return NewSQLiteStorer(cfg, logger)
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", cfg.StorageBackend)
+	}
 }
@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+// backends are the storage backends that tests of the Storer behaviour run against.
+var backends = []struct {
+	name  string
+	setup func(t *testing.T) Storer
+}{
+	{name: "file", setup: func(t *testing.T) Storer { s, _ := setupTestStorer(t); return s }},
+	{name: "sqlite", setup: func(t *testing.T) Storer { s, _ := setupTestSQLiteStorer(t); return s }},
+}
+
+// TestStorer_HistoryQueries runs the same history queries against every storage backend.
+func TestStorer_HistoryQueries(t *testing.T) {
+	day1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day4 := day1.AddDate(0, 0, 3)
+	snapshots := []domain.Snapshot{
+		{Date: day1, Repositories: []*domain.Repository{
+			{ID: 1, FullName: "owner/repo1", Stars: 10, Status: domain.StatusOK},
+			{ID: 2, FullName: "owner/repo2", Stars: 20, Status: domain.StatusOK},
+		}},
+		{Date: day2, Repositories: []*domain.Repository{
+			{ID: 2, FullName: "owner/repo2", Stars: 21, Status: domain.StatusOK},
+			{ID: 1, FullName: "owner/repo1", Stars: 11, Status: domain.StatusOK},
+		}},
+		{Date: day4, Repositories: []*domain.Repository{
+			{ID: 1, FullName: "owner/repo1", Stars: 14, Status: domain.StatusOK},
+		}},
+	}
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			storer := backend.setup(t)
+
+			_, err := storer.LoadLatest()
+			require.ErrorIs(t, err, ErrDataNotFound)
+
+			for _, snapshot := range snapshots {
+				require.NoError(t, storer.Save(snapshot.Date, snapshot.Repositories))
+			}
+
+			loaded, err := storer.LoadRange(day1, day4)
+			require.NoError(t, err)
+			assert.Equal(t, snapshots, loaded)
+
+			loaded, err = storer.LoadRange(day2, day2.AddDate(0, 0, 1))
+			require.NoError(t, err)
+			assert.Equal(t, snapshots[1:2], loaded)
+
+			series, err := storer.LoadSeries("Owner/Repo1", day2, day4)
+			require.NoError(t, err)
+			assert.Equal(t, []domain.SeriesPoint{
+				{Date: day2, Repository: snapshots[1].Repositories[1]},
+				{Date: day4, Repository: snapshots[2].Repositories[0]},
+			}, series)
+
+			latest, err := storer.LoadLatest()
+			require.NoError(t, err)
+			assert.Equal(t, &snapshots[2], latest)
+		})
+	}
+}
+
+// TestStorer_Merge checks that a second update of the day keeps what the first one fetched.
+func TestStorer_Merge(t *testing.T) {
+	date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	morning := date.Add(8 * time.Hour)
+	evening := date.Add(18 * time.Hour)
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			storer := backend.setup(t)
+
+			require.NoError(t, storer.Merge(date, []*domain.Repository{
+				{ID: 1, FullName: "owner/repo1", Stars: 10, Status: domain.StatusOK, FetchedAt: morning,
+					Samples: []domain.Sample{{FetchedAt: morning, Stars: 10}}},
+				{ID: 2, FullName: "owner/repo2", Stars: 20, Status: domain.StatusOK, FetchedAt: morning},
+			}))
+			require.NoError(t, storer.Merge(date, []*domain.Repository{
+				{ID: 1, FullName: "owner/repo1", Stars: 12, Status: domain.StatusOK, FetchedAt: evening,
+					Samples: []domain.Sample{{FetchedAt: evening, Stars: 12}}},
+				{FullName: "owner/repo2", Status: domain.StatusFailed},
+			}))
+
+			repos, err := storer.Load(date)
+			require.NoError(t, err)
+			assert.Equal(t, []*domain.Repository{
+				{ID: 1, FullName: "owner/repo1", Stars: 12, Status: domain.StatusOK, FetchedAt: evening,
+					Samples: []domain.Sample{{FetchedAt: morning, Stars: 10}, {FetchedAt: evening, Stars: 12}}},
+				{ID: 2, FullName: "owner/repo2", Stars: 20, Status: domain.StatusOK, FetchedAt: morning},
+			}, repos)
+		})
+	}
+}
+
+// TestStorer_HistoryQueriesOmitSamples checks that history queries return the value of each day
+// without the intra-day samples, which only Load returns.
+func TestStorer_HistoryQueriesOmitSamples(t *testing.T) {
+	date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	fetchedAt := date.Add(8 * time.Hour)
+
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			storer := backend.setup(t)
+			require.NoError(t, storer.Save(date, []*domain.Repository{
+				{ID: 1, FullName: "owner/repo", Stars: 10, Status: domain.StatusOK, FetchedAt: fetchedAt,
+					Samples: []domain.Sample{{FetchedAt: fetchedAt, Stars: 10}}},
+			}))
+			want := &domain.Repository{ID: 1, FullName: "owner/repo", Stars: 10, Status: domain.StatusOK, FetchedAt: fetchedAt}
+
+			snapshots, err := storer.LoadRange(date, date)
+			require.NoError(t, err)
+			require.Len(t, snapshots, 1)
+			assert.Equal(t, []*domain.Repository{want}, snapshots[0].Repositories)
+
+			series, err := storer.LoadSeries("owner/repo", date, date)
+			require.NoError(t, err)
+			assert.Equal(t, []domain.SeriesPoint{{Date: date, Repository: want}}, series)
+
+			repos, err := storer.Load(date)
+			require.NoError(t, err)
+			assert.Len(t, repos[0].Samples, 1)
+		})
+	}
+}
+
+func TestFileStorer_IndexFollowsDataFiles(t *testing.T) {
+	storer, cfg := setupTestStorer(t)
+	day1 := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	january := filepath.Join(storer.getIndexDir(), "2025-01.json")
+	february := filepath.Join(storer.getIndexDir(), "2025-02.json")
+
+	require.NoError(t, storer.Save(day1, []*domain.Repository{{FullName: "owner/repo", Stars: 1}}))
+	require.NoError(t, storer.Save(day2, []*domain.Repository{{FullName: "owner/repo", Stars: 2}}))
+	_, err := storer.LoadRange(day2, day2)
+	require.NoError(t, err)
+	// Only the months of the range are indexed, outside the data directory.
+	assert.FileExists(t, february)
+	assert.NoFileExists(t, january)
+	assert.True(t, strings.HasPrefix(storer.getIndexDir(), cfg.IndexCacheDir))
+	_, err = storer.LoadRange(day1, day2)
+	require.NoError(t, err)
+	assert.FileExists(t, january)
+
+	// A data file whose size and modification time didn't change isn't read again.
+	path := storer.getDailyDataPath(day1)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, bytes.Repeat([]byte{' '}, len(data)), 0644))
+	require.NoError(t, os.Chtimes(path, info.ModTime(), info.ModTime()))
+	snapshots, err := storer.LoadRange(day1, day1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, snapshots[0].Repositories[0].Stars)
+
+	// A data file touched, e.g. by a checkout, is read again but only decoded if its content changed.
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	require.NoError(t, os.Chtimes(path, info.ModTime().Add(time.Hour), info.ModTime().Add(time.Hour)))
+	snapshots, err = storer.LoadRange(day1, day1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, snapshots[0].Repositories[0].Stars)
+
+	// A data file rewritten since it was indexed is read again, and a removed one is dropped.
+	require.NoError(t, storer.Save(day1, []*domain.Repository{{FullName: "owner/repo", Stars: 100}}))
+	require.NoError(t, os.Remove(storer.getDailyDataPath(day2)))
+
+	snapshots, err = storer.LoadRange(day1, day2)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, 100, snapshots[0].Repositories[0].Stars)
+
+	// A corrupt index is rebuilt.
+	require.NoError(t, os.WriteFile(january, []byte("{"), 0644))
+	snapshots, err = storer.LoadRange(day1, day2)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, 100, snapshots[0].Repositories[0].Stars)
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/yourname/go-trendboard/internal/infra/storage"
+)
+
+// CompactionReport is the outcome of compacting the saved snapshots.
+type CompactionReport struct {
+	// Archived is the number of snapshots moved into archives.
+	Archived int
+	// Dropped is the number of snapshots deleted by the weekly archive granularity.
+	Dropped int
+}
+
+// Compact moves the snapshots older than DailyRetentionDays into compressed archives, which the
+// storer keeps reading transparently. With the weekly ArchiveGranularity, only the last of those
+// snapshots of each week is kept.
+func (u *Usecase) Compact(ctx context.Context) (*CompactionReport, error) {
+	compactor, ok := u.storer.(storage.Compactor)
+	if !ok {
+		u.logger.Error("The storage backend does not support compaction")
+		return nil, errors.New("the storage backend does not support compaction")
+	}
+
+	// Weekly trends compare with the snapshot of a week ago, which must not be dropped.
+	if u.cfg.ArchiveGranularity == "weekly" && u.cfg.DailyRetentionDays < 7 {
+		u.logger.Error("Daily retention is too short for the weekly archive granularity", "days", u.cfg.DailyRetentionDays)
+		return nil, fmt.Errorf("daily_retention_days must be at least 7 with weekly archive_granularity, got %d", u.cfg.DailyRetentionDays)
+	}
+
+	dates, err := u.storer.ListDates()
+	if err != nil {
+		u.logger.Error("Failed to list saved snapshots", "error", err)
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	cutoff := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -u.cfg.DailyRetentionDays)
+	u.logger.Info("Compacting snapshots...", "before", cutoff.Format("2006-01-02"), "granularity", u.cfg.ArchiveGranularity)
+
+	var archive, drop []time.Time
+	for i, date := range dates {
+		if !date.Before(cutoff) {
+			break
+		}
+		// Dates are in ascending order, so the last date of a week is followed by another week or by none.
+		if u.cfg.ArchiveGranularity == "weekly" && i+1 < len(dates) && dates[i+1].Before(cutoff) && sameWeek(date, dates[i+1]) {
+			drop = append(drop, date)
+			continue
+		}
+		archive = append(archive, date)
+	}
+	if len(archive) == 0 && len(drop) == 0 {
+		u.logger.Info("No snapshots to compact")
+		return &CompactionReport{}, nil
+	}
+
+	archived, dropped, err := compactor.Compact(archive, drop)
+	if err != nil {
+		u.logger.Error("Failed to compact snapshots", "error", err)
+		return nil, fmt.Errorf("failed to compact snapshots: %w", err)
+	}
+
+	u.logger.Info("Compacted snapshots", "archived", archived, "dropped", dropped)
+	return &CompactionReport{Archived: archived, Dropped: dropped}, nil
+}
+
+// sameWeek reports whether a and b fall in the same ISO week.
+func sameWeek(a, b time.Time) bool {
+	aYear, aWeek := a.ISOWeek()
+	bYear, bWeek := b.ISOWeek()
+	return aYear == bYear && aWeek == bWeek
+}
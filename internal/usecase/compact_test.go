@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MockCompactingStorer is a MockStorer that also implements storage.Compactor.
+type MockCompactingStorer struct {
+	MockStorer
+}
+
+func (m *MockCompactingStorer) Compact(archive, drop []time.Time) (int, int, error) {
+	args := m.Called(archive, drop)
+	return args.Int(0), args.Int(1), args.Error(2)
+}
+
+func TestUsecase_Compact(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	// Days of a single ISO week, well before the retention period.
+	monday := today.AddDate(0, 0, -60)
+	for monday.Weekday() != time.Monday {
+		monday = monday.AddDate(0, 0, -1)
+	}
+	tuesday, sunday := monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 6)
+	nextMonday := monday.AddDate(0, 0, 7)
+	recent := today.AddDate(0, 0, -1)
+	dates := []time.Time{monday, tuesday, sunday, nextMonday, recent}
+
+	t.Run("Daily granularity archives every old snapshot", func(t *testing.T) {
+		uc, _, _, cfg := setupTestUsecase(t)
+		cfg.DailyRetentionDays = 30
+		cfg.ArchiveGranularity = "daily"
+		storer := new(MockCompactingStorer)
+		uc.storer = storer
+		storer.On("ListDates").Return(dates, nil).Once()
+		storer.On("Compact", []time.Time{monday, tuesday, sunday, nextMonday}, []time.Time(nil)).Return(4, 0, nil).Once()
+
+		report, err := uc.Compact(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, &CompactionReport{Archived: 4}, report)
+		storer.AssertExpectations(t)
+	})
+
+	t.Run("Weekly granularity keeps the last snapshot of each week", func(t *testing.T) {
+		uc, _, _, cfg := setupTestUsecase(t)
+		cfg.DailyRetentionDays = 30
+		cfg.ArchiveGranularity = "weekly"
+		storer := new(MockCompactingStorer)
+		uc.storer = storer
+		storer.On("ListDates").Return(dates, nil).Once()
+		storer.On("Compact", []time.Time{sunday, nextMonday}, []time.Time{monday, tuesday}).Return(2, 2, nil).Once()
+
+		report, err := uc.Compact(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, &CompactionReport{Archived: 2, Dropped: 2}, report)
+		storer.AssertExpectations(t)
+	})
+
+	t.Run("Weekly granularity needs a week of daily snapshots", func(t *testing.T) {
+		uc, _, _, cfg := setupTestUsecase(t)
+		cfg.DailyRetentionDays = 3
+		cfg.ArchiveGranularity = "weekly"
+		storer := new(MockCompactingStorer)
+		uc.storer = storer
+
+		_, err := uc.Compact(context.Background())
+		require.Error(t, err)
+		storer.AssertNotCalled(t, "ListDates")
+	})
+
+	t.Run("Storer without compaction", func(t *testing.T) {
+		uc, _, storer, _ := setupTestUsecase(t)
+
+		_, err := uc.Compact(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not support compaction")
+		storer.AssertNotCalled(t, "ListDates")
+	})
+}
@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+	"github.com/yourname/go-trendboard/internal/infra/github"
+	"github.com/yourname/go-trendboard/internal/infra/storage"
+)
+
+// DiscoveredTag is the tag given to repositories added to the list by Discover.
+const DiscoveredTag = "discovered"
+
+// relativeDatePattern matches the "{today}" and "{today-Nd}" placeholders of discover queries.
+var relativeDatePattern = regexp.MustCompile(`\{today(?:-(\d+)d)?\}`)
+
+// Discover runs the configured search queries and returns the repositories found that aren't
+// tracked yet, most starred first within each query. With add, they are appended to the
+// repositories file tagged as DiscoveredTag, and the names that were added are returned too.
+func (u *Usecase) Discover(ctx context.Context, add bool) (candidates []*domain.Repository, added []string, err error) {
+	u.logger.Info("Discovering repositories...", "queries", len(u.cfg.DiscoverQueries))
+
+	searcher, ok := u.fetcher.(github.Searcher)
+	if !ok {
+		return nil, nil, fmt.Errorf("the configured fetcher does not support searching repositories")
+	}
+	if len(u.cfg.DiscoverQueries) == 0 {
+		return nil, nil, fmt.Errorf("no discover queries configured: set DISCOVER_QUERIES")
+	}
+
+	targets, err := u.storer.LoadTargetRepos()
+	if err != nil && !errors.Is(err, storage.ErrReposConfigNotFound) {
+		u.logger.Error("Failed to load target repositories", "error", err)
+		return nil, nil, fmt.Errorf("failed to load target repos: %w", err)
+	}
+	known := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		known[strings.ToLower(target.Name)] = true
+	}
+
+	now := time.Now().UTC()
+	for _, query := range u.cfg.DiscoverQueries {
+		query = expandSearchQuery(query, now)
+		repos, err := searcher.SearchRepos(ctx, query, u.cfg.DiscoverLimit)
+		if err != nil {
+			return nil, nil, err // Already logged by the searcher
+		}
+		for _, repo := range repos {
+			if known[repo.Key()] {
+				continue
+			}
+			known[repo.Key()] = true
+			candidates = append(candidates, repo)
+		}
+	}
+	u.logger.Info("Discovered candidate repositories", "count", len(candidates))
+
+	if !add || len(candidates) == 0 {
+		return candidates, nil, nil
+	}
+	names := make([]string, len(candidates))
+	for i, repo := range candidates {
+		names[i] = repo.FullName
+	}
+	if added, err = u.AddRepos(ctx, names, []string{DiscoveredTag}, false); err != nil {
+		return nil, nil, err
+	}
+	return candidates, added, nil
+}
+
+// expandSearchQuery replaces the "{today}" and "{today-Nd}" placeholders of a query with dates relative to now.
+func expandSearchQuery(query string, now time.Time) string {
+	return relativeDatePattern.ReplaceAllStringFunc(query, func(placeholder string) string {
+		days := 0
+		if m := relativeDatePattern.FindStringSubmatch(placeholder); m[1] != "" {
+			days, _ = strconv.Atoi(m[1])
+		}
+		return now.AddDate(0, 0, -days).Format("2006-01-02")
+	})
+}
@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+type MockSearchFetcher struct {
+	MockFetcher
+}
+
+func (m *MockSearchFetcher) SearchRepos(ctx context.Context, query string, limit int) ([]*domain.Repository, error) {
+	args := m.Called(ctx, query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Repository), args.Error(1)
+}
+
+func TestUsecase_Discover(t *testing.T) {
+	newRepo := func(name string, stars int) *domain.Repository {
+		repo, err := domain.NewRepository(name, stars)
+		require.NoError(t, err)
+		return repo
+	}
+
+	setup := func(t *testing.T) (*Usecase, *MockSearchFetcher, *MockStorer) {
+		_, _, storer, cfg := setupTestUsecase(t)
+		cfg.DiscoverQueries = []string{"language:Go", "topic:cli"}
+		cfg.DiscoverLimit = 10
+		searcher := new(MockSearchFetcher)
+		logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+		uc := NewUsecase(cfg, logger, searcher, storer)
+
+		storer.On("LoadTargetRepos").Return(targets("spf13/cobra"), nil)
+		searcher.On("SearchRepos", mock.Anything, "language:Go", 10).Return([]*domain.Repository{newRepo("New/Rising", 900), newRepo("SPF13/Cobra", 800)}, nil).Once()
+		searcher.On("SearchRepos", mock.Anything, "topic:cli", 10).Return([]*domain.Repository{newRepo("new/rising", 900), newRepo("owner/tool", 600)}, nil).Once()
+		return uc, searcher, storer
+	}
+
+	t.Run("Lists new candidates", func(t *testing.T) {
+		uc, searcher, storer := setup(t)
+
+		candidates, added, err := uc.Discover(context.Background(), false)
+		require.NoError(t, err)
+		assert.Empty(t, added)
+		require.Len(t, candidates, 2)
+		assert.Equal(t, "New/Rising", candidates[0].FullName)
+		assert.Equal(t, "owner/tool", candidates[1].FullName)
+		searcher.AssertExpectations(t)
+		storer.AssertNotCalled(t, "SaveTargetRepos", mock.Anything)
+	})
+
+	t.Run("Adds candidates with the discovered tag", func(t *testing.T) {
+		uc, _, storer := setup(t)
+		storer.On("SaveTargetRepos", []domain.TargetRepo{
+			{Name: "spf13/cobra"},
+			{Name: "new/rising", Tags: []string{DiscoveredTag}},
+			{Name: "owner/tool", Tags: []string{DiscoveredTag}},
+		}).Return(nil).Once()
+
+		_, added, err := uc.Discover(context.Background(), true)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"new/rising", "owner/tool"}, added)
+		storer.AssertExpectations(t)
+	})
+
+	t.Run("Fetcher without search support", func(t *testing.T) {
+		uc, _, _, cfg := setupTestUsecase(t)
+		cfg.DiscoverQueries = []string{"language:Go"}
+
+		_, _, err := uc.Discover(context.Background(), false)
+		require.Error(t, err)
+	})
+}
+
+func TestExpandSearchQuery(t *testing.T) {
+	now := time.Date(2025, 11, 30, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, "language:Go created:>2025-09-01 pushed:>2025-11-30", expandSearchQuery("language:Go created:>{today-90d} pushed:>{today}", now))
+	assert.Equal(t, "stars:>500", expandSearchQuery("stars:>500", now))
+}
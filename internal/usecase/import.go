@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+	"github.com/yourname/go-trendboard/internal/infra/storage"
+)
+
+// githubLinkPattern matches links to github.com and captures their path.
+var githubLinkPattern = regexp.MustCompile(`https?://(?:www\.)?github\.com/([^\s()\[\]<>"'#?]+)`)
+
+// reservedOwners are first path segments of github.com URLs that don't name an account.
+var reservedOwners = map[string]bool{
+	"about": true, "apps": true, "collections": true, "contact": true, "customer-stories": true,
+	"enterprise": true, "events": true, "explore": true, "features": true, "login": true,
+	"marketplace": true, "new": true, "notifications": true, "orgs": true, "pricing": true,
+	"pulls": true, "search": true, "security": true, "settings": true, "site": true,
+	"sponsors": true, "topics": true, "trending": true, "users": true,
+}
+
+// ImportReport is the outcome of importing repositories from a Markdown file.
+type ImportReport struct {
+	// Added are the repositories added to the tracked list.
+	Added []string
+	// Duplicates are the repositories that were already tracked or linked more than once.
+	Duplicates []string
+	// Invalid are the links that don't point to a repository, such as links to a user profile
+	// or to GitHub pages like https://github.com/topics/go.
+	Invalid []string
+}
+
+// ImportRepos extracts links to GitHub repositories from a Markdown file, such as an
+// awesome-go style list, and merges them into the tracked list with the given tags.
+// Links deeper into a repository (e.g. to a file) count as the repository itself.
+// With dryRun, the report is computed but the tracked list is left untouched.
+func (u *Usecase) ImportRepos(ctx context.Context, path string, tags []string, dryRun bool) (*ImportReport, error) {
+	u.logger.Info("Importing repositories...", "path", path)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		u.logger.Error("Failed to read import file", "path", path, "error", err)
+		return nil, fmt.Errorf("failed to read '%s': %w", path, err)
+	}
+
+	targets, err := u.storer.LoadTargetRepos()
+	if err != nil && !errors.Is(err, storage.ErrReposConfigNotFound) {
+		u.logger.Error("Failed to load target repositories", "error", err)
+		return nil, fmt.Errorf("failed to load target repos: %w", err)
+	}
+	tracked := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		tracked[strings.ToLower(target.Name)] = true
+	}
+
+	report := &ImportReport{}
+	for _, link := range githubLinkPattern.FindAllStringSubmatch(string(content), -1) {
+		segments := strings.Split(strings.Trim(link[1], "/"), "/")
+		if len(segments) < 2 || reservedOwners[strings.ToLower(segments[0])] {
+			report.Invalid = append(report.Invalid, link[0])
+			continue
+		}
+		name, err := domain.NormalizeRepoName(segments[0] + "/" + segments[1])
+		if err != nil {
+			report.Invalid = append(report.Invalid, link[0])
+			continue
+		}
+		if tracked[name] {
+			report.Duplicates = append(report.Duplicates, name)
+			continue
+		}
+		tracked[name] = true
+		report.Added = append(report.Added, name)
+
+		target := domain.TargetRepo{Name: name}
+		target.AddTags(tags...)
+		targets = append(targets, target)
+	}
+	u.logger.Info("Parsed import file", "path", path, "new", len(report.Added), "duplicates", len(report.Duplicates), "invalid", len(report.Invalid))
+
+	if dryRun || len(report.Added) == 0 {
+		return report, nil
+	}
+	if err := u.storer.SaveTargetRepos(targets); err != nil {
+		u.logger.Error("Failed to save target repositories", "error", err)
+		return nil, fmt.Errorf("failed to save target repos: %w", err)
+	}
+	u.logger.Info("Imported repositories", "count", len(report.Added))
+	return report, nil
+}
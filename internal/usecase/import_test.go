@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+const awesomeList = `# Awesome Go
+
+[![Sponsor](https://img.shields.io/badge)](https://github.com/sponsors/avelino)
+
+## Web Frameworks
+
+- [Gin](https://github.com/gin-gonic/gin) - HTTP web framework.
+- [chi](https://github.com/go-chi/chi/) - Lightweight router.
+- [Echo](https://www.github.com/LabStack/Echo) - High performance framework.
+- [gin again](https://github.com/Gin-Gonic/Gin#readme) - Linked twice.
+- [cobra docs](https://github.com/spf13/cobra/blob/main/README.md) - Deep link.
+- [A person](https://github.com/someone) - Profile link.
+- [Topic](https://github.com/topics/go) - Not a repository.
+`
+
+func TestUsecase_ImportRepos(t *testing.T) {
+	setup := func(t *testing.T) (*Usecase, *MockStorer, string) {
+		uc, _, storer, _ := setupTestUsecase(t)
+		path := filepath.Join(t.TempDir(), "README.md")
+		require.NoError(t, os.WriteFile(path, []byte(awesomeList), 0644))
+		storer.On("LoadTargetRepos").Return(targets("go-chi/chi"), nil).Once()
+		return uc, storer, path
+	}
+
+	t.Run("Merges new repositories", func(t *testing.T) {
+		uc, storer, path := setup(t)
+		storer.On("SaveTargetRepos", []domain.TargetRepo{
+			{Name: "go-chi/chi"},
+			{Name: "gin-gonic/gin", Tags: []string{"awesome"}},
+			{Name: "labstack/echo", Tags: []string{"awesome"}},
+			{Name: "spf13/cobra", Tags: []string{"awesome"}},
+		}).Return(nil).Once()
+
+		report, err := uc.ImportRepos(context.Background(), path, []string{"awesome"}, false)
+		require.NoError(t, err)
+		assert.Equal(t, &ImportReport{
+			Added:      []string{"gin-gonic/gin", "labstack/echo", "spf13/cobra"},
+			Duplicates: []string{"go-chi/chi", "gin-gonic/gin"},
+			Invalid:    []string{"https://github.com/sponsors/avelino", "https://github.com/someone", "https://github.com/topics/go"},
+		}, report)
+		storer.AssertExpectations(t)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		uc, storer, path := setup(t)
+
+		report, err := uc.ImportRepos(context.Background(), path, nil, true)
+		require.NoError(t, err)
+		assert.Len(t, report.Added, 3)
+		storer.AssertNotCalled(t, "SaveTargetRepos", mock.Anything)
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		uc, _, _, _ := setupTestUsecase(t)
+
+		_, err := uc.ImportRepos(context.Background(), filepath.Join(t.TempDir(), "missing.md"), nil, false)
+		require.Error(t, err)
+	})
+}
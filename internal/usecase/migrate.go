@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+	"github.com/yourname/go-trendboard/internal/infra/storage"
+)
+
+// IDMigrationReport is the outcome of filling in GitHub IDs in saved snapshots.
+type IDMigrationReport struct {
+	// Snapshots is the number of snapshots that were read.
+	Snapshots int
+	// Updated is the number of snapshots that were rewritten with IDs.
+	Updated int
+	// Unresolved are the repositories whose ID could not be determined.
+	Unresolved []string
+}
+
+// MigrateIDs fills in the GitHub ID of the repositories in snapshots saved before IDs were
+// recorded. IDs are taken from other snapshots of the same repository, following recorded
+// renames. With fetch, the IDs of repositories no snapshot knows are looked up on GitHub.
+func (u *Usecase) MigrateIDs(ctx context.Context, fetch bool) (*IDMigrationReport, error) {
+	u.logger.Info("Migrating snapshots to GitHub IDs...")
+
+	dates, err := u.storer.ListDates()
+	if err != nil {
+		u.logger.Error("Failed to list saved snapshots", "error", err)
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	renames := u.loadRenameMap()
+
+	snapshots := make([][]*domain.Repository, len(dates))
+	ids := make(map[string]int64)
+	for i, date := range dates {
+		repos, err := u.storer.Load(date)
+		if err != nil {
+			u.logger.Error("Failed to load snapshot", "date", date.Format("2006-01-02"), "error", err)
+			return nil, fmt.Errorf("failed to load snapshot of %s: %w", date.Format("2006-01-02"), err)
+		}
+		snapshots[i] = repos
+		for _, repo := range repos {
+			if repo.ID != 0 {
+				ids[renames.ResolveKey(repo.FullName)] = repo.ID
+			}
+		}
+	}
+
+	var missing []string
+	for _, repos := range snapshots {
+		for _, repo := range repos {
+			if key := renames.ResolveKey(repo.FullName); repo.ID == 0 && ids[key] == 0 && !slices.Contains(missing, key) {
+				missing = append(missing, key)
+			}
+		}
+	}
+	if fetch && len(missing) > 0 {
+		u.logger.Info("Looking up IDs on GitHub", "count", len(missing))
+		for _, result := range u.fetchAll(ctx, missing) {
+			if result.Err != nil {
+				u.logger.Warn("Failed to look up repository ID", "repo", result.RepoName, "error", result.Err)
+				continue
+			}
+			ids[result.RepoName] = result.Repository.ID
+		}
+	}
+
+	report := &IDMigrationReport{Snapshots: len(dates)}
+	for _, key := range missing {
+		if ids[key] == 0 {
+			report.Unresolved = append(report.Unresolved, key)
+		}
+	}
+	for i, repos := range snapshots {
+		changed := false
+		for _, repo := range repos {
+			if id := ids[renames.ResolveKey(repo.FullName)]; repo.ID == 0 && id != 0 {
+				repo.ID = id
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		if err := u.storer.Save(dates[i], repos); err != nil {
+			u.logger.Error("Failed to save migrated snapshot", "date", dates[i].Format("2006-01-02"), "error", err)
+			return nil, fmt.Errorf("failed to save snapshot of %s: %w", dates[i].Format("2006-01-02"), err)
+		}
+		report.Updated++
+	}
+
+	u.logger.Info("Migrated snapshots to GitHub IDs", "snapshots", report.Snapshots, "updated", report.Updated, "unresolved", len(report.Unresolved))
+	return report, nil
+}
+
+// MigrateSchema rewrites the snapshots saved in older formats in the current one, if the
+// configured storer supports it. It returns the number of snapshots that were upgraded.
+func (u *Usecase) MigrateSchema(ctx context.Context) (int, error) {
+	upgrader, ok := u.storer.(storage.SchemaUpgrader)
+	if !ok {
+		u.logger.Info("The storage backend upgrades its data on its own; nothing to migrate")
+		return 0, nil
+	}
+
+	u.logger.Info("Upgrading snapshots to the current format...")
+	upgraded, err := upgrader.UpgradeSchema()
+	if err != nil {
+		u.logger.Error("Failed to upgrade snapshots", "error", err)
+		return upgraded, fmt.Errorf("failed to upgrade snapshots: %w", err)
+	}
+
+	u.logger.Info("Upgraded snapshots to the current format", "upgraded", upgraded)
+	return upgraded, nil
+}
+
+// CopyData copies every snapshot and the recorded renames from the configured storer to dst,
+// replacing the snapshots of the same dates in dst. It returns the number of snapshots copied.
+func (u *Usecase) CopyData(ctx context.Context, dst storage.Storer) (int, error) {
+	u.logger.Info("Copying trend data...")
+
+	dates, err := u.storer.ListDates()
+	if err != nil {
+		u.logger.Error("Failed to list saved snapshots", "error", err)
+		return 0, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	for i, date := range dates {
+		if err := ctx.Err(); err != nil {
+			return i, err
+		}
+		repos, err := u.storer.Load(date)
+		if err != nil {
+			u.logger.Error("Failed to load snapshot", "date", date.Format("2006-01-02"), "error", err)
+			return i, fmt.Errorf("failed to load snapshot of %s: %w", date.Format("2006-01-02"), err)
+		}
+		if err := dst.Save(date, repos); err != nil {
+			u.logger.Error("Failed to copy snapshot", "date", date.Format("2006-01-02"), "error", err)
+			return i, fmt.Errorf("failed to copy snapshot of %s: %w", date.Format("2006-01-02"), err)
+		}
+	}
+
+	renames, err := u.storer.LoadRenames()
+	if err != nil {
+		u.logger.Error("Failed to load recorded renames", "error", err)
+		return len(dates), fmt.Errorf("failed to load renames: %w", err)
+	}
+	if len(renames) > 0 {
+		if err := dst.SaveRenames(renames); err != nil {
+			u.logger.Error("Failed to copy recorded renames", "error", err)
+			return len(dates), fmt.Errorf("failed to copy renames: %w", err)
+		}
+	}
+
+	u.logger.Info("Copied trend data", "snapshots", len(dates), "renames", len(renames))
+	return len(dates), nil
+}
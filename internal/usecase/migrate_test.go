@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+func TestUsecase_MigrateIDs(t *testing.T) {
+	day1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	setup := func(t *testing.T) (*Usecase, *MockFetcher, *MockStorer) {
+		uc, fetcher, storer, _ := setupTestUsecase(t)
+		storer.On("ListDates").Return([]time.Time{day1, day2}, nil).Once()
+		storer.On("LoadRenames").Return([]domain.Rename{{From: "old/name", To: "new/name"}}, nil).Once()
+		storer.On("Load", day1).Return([]*domain.Repository{
+			{FullName: "old/name", Stars: 10},
+			{FullName: "owner/legacy", Stars: 20},
+		}, nil).Once()
+		storer.On("Load", day2).Return([]*domain.Repository{
+			{ID: 1, FullName: "new/name", Stars: 11},
+			{FullName: "owner/legacy", Stars: 21},
+		}, nil).Once()
+		return uc, fetcher, storer
+	}
+
+	t.Run("With lookup on GitHub", func(t *testing.T) {
+		uc, fetcher, storer := setup(t)
+		fetcher.On("FetchStars", mock.Anything, "owner/legacy").Return(&domain.Repository{ID: 2, FullName: "owner/legacy", Stars: 22}, nil).Once()
+		storer.On("Save", day1, []*domain.Repository{
+			{ID: 1, FullName: "old/name", Stars: 10},
+			{ID: 2, FullName: "owner/legacy", Stars: 20},
+		}).Return(nil).Once()
+		storer.On("Save", day2, []*domain.Repository{
+			{ID: 1, FullName: "new/name", Stars: 11},
+			{ID: 2, FullName: "owner/legacy", Stars: 21},
+		}).Return(nil).Once()
+
+		report, err := uc.MigrateIDs(context.Background(), true)
+		require.NoError(t, err)
+		assert.Equal(t, &IDMigrationReport{Snapshots: 2, Updated: 2}, report)
+		storer.AssertExpectations(t)
+		fetcher.AssertExpectations(t)
+	})
+
+	t.Run("Offline", func(t *testing.T) {
+		uc, fetcher, storer := setup(t)
+		storer.On("Save", day1, []*domain.Repository{
+			{ID: 1, FullName: "old/name", Stars: 10},
+			{FullName: "owner/legacy", Stars: 20},
+		}).Return(nil).Once()
+
+		report, err := uc.MigrateIDs(context.Background(), false)
+		require.NoError(t, err)
+		assert.Equal(t, &IDMigrationReport{Snapshots: 2, Updated: 1, Unresolved: []string{"owner/legacy"}}, report)
+		storer.AssertExpectations(t)
+		fetcher.AssertNotCalled(t, "FetchStars", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUsecase_CopyData(t *testing.T) {
+	uc, _, src, _ := setupTestUsecase(t)
+	dst := new(MockStorer)
+
+	day1 := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	repos1 := []*domain.Repository{{ID: 1, FullName: "owner/repo", Stars: 10}}
+	repos2 := []*domain.Repository{{ID: 1, FullName: "owner/repo", Stars: 11}}
+	renames := []domain.Rename{{From: "old/name", To: "owner/repo"}}
+
+	src.On("ListDates").Return([]time.Time{day1, day2}, nil).Once()
+	src.On("Load", day1).Return(repos1, nil).Once()
+	src.On("Load", day2).Return(repos2, nil).Once()
+	src.On("LoadRenames").Return(renames, nil).Once()
+	dst.On("Save", day1, repos1).Return(nil).Once()
+	dst.On("Save", day2, repos2).Return(nil).Once()
+	dst.On("SaveRenames", renames).Return(nil).Once()
+
+	copied, err := uc.CopyData(context.Background(), dst)
+	require.NoError(t, err)
+	assert.Equal(t, 2, copied)
+	dst.AssertExpectations(t)
+}
+
+// MockUpgradingStorer is a MockStorer that also implements storage.SchemaUpgrader.
+type MockUpgradingStorer struct {
+	MockStorer
+}
+
+func (m *MockUpgradingStorer) UpgradeSchema() (int, error) {
+	args := m.Called()
+	return args.Int(0), args.Error(1)
+}
+
+func TestUsecase_MigrateSchema(t *testing.T) {
+	t.Run("Storer upgrades its data", func(t *testing.T) {
+		uc, _, _, _ := setupTestUsecase(t)
+		storer := new(MockUpgradingStorer)
+		uc.storer = storer
+		storer.On("UpgradeSchema").Return(3, nil).Once()
+
+		upgraded, err := uc.MigrateSchema(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 3, upgraded)
+		storer.AssertExpectations(t)
+	})
+
+	t.Run("Storer upgrades its data on its own", func(t *testing.T) {
+		uc, _, storer, _ := setupTestUsecase(t)
+
+		upgraded, err := uc.MigrateSchema(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, upgraded)
+		storer.AssertExpectations(t)
+	})
+}
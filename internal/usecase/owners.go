@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+	"github.com/yourname/go-trendboard/internal/infra/github"
+)
+
+// expandTargets returns the names of the repositories to update: the enabled repository
+// entries followed by the repositories of the enabled owner entries that pass their filters.
+// Owners are listed on every run so that new repositories are picked up. Repositories with a
+// disabled entry of their own are never included, and an owner that can't be listed is skipped.
+func (u *Usecase) expandTargets(ctx context.Context, targets []domain.TargetRepo) []string {
+	seen := make(map[string]bool, len(targets))
+	var names []string
+	var owners []domain.TargetRepo
+	for _, target := range targets {
+		if _, ok := target.Owner(); ok {
+			if !target.Disabled {
+				owners = append(owners, target)
+			}
+			continue
+		}
+		key := strings.ToLower(target.Name)
+		if seen[key] {
+			continue
+		}
+		// Disabled entries are marked as seen so that owner entries don't bring them back.
+		seen[key] = true
+		if !target.Disabled {
+			names = append(names, target.Name)
+		}
+	}
+	if len(owners) == 0 {
+		return names
+	}
+
+	lister, ok := u.fetcher.(github.Lister)
+	if !ok {
+		u.logger.Warn("The configured fetcher can't list owner repositories, skipping owner entries", "count", len(owners))
+		return names
+	}
+	for _, target := range owners {
+		owner, _ := target.Owner()
+		repos, err := lister.ListOwnerRepos(ctx, owner)
+		if err != nil {
+			u.logger.Warn("Failed to list owner repositories, skipping", "owner", owner, "error", err)
+			continue
+		}
+		added := 0
+		for _, repo := range repos {
+			key := strings.ToLower(repo.FullName)
+			if seen[key] || !target.Includes(repo) {
+				continue
+			}
+			seen[key] = true
+			names = append(names, repo.FullName)
+			added++
+		}
+		u.logger.Info("Expanded owner entry", "owner", owner, "listed", len(repos), "included", added)
+	}
+	return names
+}
+
+// targetsByRepo maps lower-cased repository names to their entries in the tracked list.
+// Repositories that aren't listed themselves but belong to an owner entry get an entry
+// inheriting the owner entry's tags; fullNames are the repositories to consider for that.
+func targetsByRepo(targets []domain.TargetRepo, fullNames []string) map[string]*domain.TargetRepo {
+	byRepo := make(map[string]*domain.TargetRepo, len(targets))
+	byOwner := make(map[string]domain.TargetRepo)
+	for i := range targets {
+		if owner, ok := targets[i].Owner(); ok {
+			if _, dup := byOwner[owner.Name]; !dup {
+				byOwner[owner.Name] = targets[i]
+			}
+			continue
+		}
+		byRepo[strings.ToLower(targets[i].Name)] = &targets[i]
+	}
+
+	for _, fullName := range fullNames {
+		key := strings.ToLower(fullName)
+		if _, ok := byRepo[key]; ok {
+			continue
+		}
+		ownerName, _, _ := strings.Cut(key, "/")
+		if ownerTarget, ok := byOwner[ownerName]; ok {
+			target := ownerTarget.ForOwnedRepo(fullName)
+			byRepo[key] = &target
+		}
+	}
+	return byRepo
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+type MockListerFetcher struct {
+	MockFetcher
+}
+
+func (m *MockListerFetcher) ListOwnerRepos(ctx context.Context, owner domain.Owner) ([]domain.OwnedRepo, error) {
+	args := m.Called(ctx, owner)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.OwnedRepo), args.Error(1)
+}
+
+func TestUsecase_ExpandTargets(t *testing.T) {
+	_, _, storer, cfg := setupTestUsecase(t)
+	lister := new(MockListerFetcher)
+	logger := slog.New(slog.NewJSONHandler(os.NewFile(0, os.DevNull), nil))
+	uc := NewUsecase(cfg, logger, lister, storer)
+
+	targets := []domain.TargetRepo{
+		{Name: "uber-go/zap"},
+		{Name: "org:uber-go", Language: "Go", MinStars: 100},
+		{Name: "uber-go/mock", Disabled: true},
+		{Name: "user:spf13"},
+		{Name: "org:missing"},
+		{Name: "org:disabled", Disabled: true},
+	}
+	lister.On("ListOwnerRepos", mock.Anything, domain.Owner{Kind: domain.OwnerOrg, Name: "uber-go"}).Return([]domain.OwnedRepo{
+		{FullName: "uber-go/zap", Stars: 20000, Language: "Go"},
+		{FullName: "uber-go/fx", Stars: 5000, Language: "Go"},
+		{FullName: "uber-go/mock", Stars: 2000, Language: "Go"},
+		{FullName: "uber-go/tiny", Stars: 10, Language: "Go"},
+		{FullName: "uber-go/fork", Stars: 900, Language: "Go", Fork: true},
+	}, nil).Once()
+	lister.On("ListOwnerRepos", mock.Anything, domain.Owner{Kind: domain.OwnerUser, Name: "spf13"}).Return([]domain.OwnedRepo{
+		{FullName: "spf13/cobra", Stars: 30000, Language: "Go"},
+	}, nil).Once()
+	lister.On("ListOwnerRepos", mock.Anything, domain.Owner{Kind: domain.OwnerOrg, Name: "missing"}).Return(nil, errors.New("not found")).Once()
+
+	names := uc.expandTargets(context.Background(), targets)
+	assert.Equal(t, []string{"uber-go/zap", "uber-go/fx", "spf13/cobra"}, names)
+	lister.AssertExpectations(t)
+}
+
+func TestUsecase_ExpandTargets_WithoutLister(t *testing.T) {
+	uc, _, _, _ := setupTestUsecase(t)
+
+	names := uc.expandTargets(context.Background(), []domain.TargetRepo{{Name: "owner/repo"}, {Name: "org:uber-go"}})
+	assert.Equal(t, []string{"owner/repo"}, names)
+}
+
+func TestTargetsByRepo(t *testing.T) {
+	targets := []domain.TargetRepo{
+		{Name: "uber-go/zap", DisplayName: "Zap"},
+		{Name: "org:uber-go", DisplayName: "Uber", Tags: []string{"uber"}},
+	}
+
+	byRepo := targetsByRepo(targets, []string{"uber-go/zap", "Uber-Go/fx", "spf13/cobra"})
+	require.Contains(t, byRepo, "uber-go/zap")
+	assert.Equal(t, "Zap", byRepo["uber-go/zap"].Label())
+	require.Contains(t, byRepo, "uber-go/fx")
+	assert.Equal(t, domain.TargetRepo{Name: "Uber-Go/fx", Tags: []string{"uber"}}, *byRepo["uber-go/fx"])
+	assert.NotContains(t, byRepo, "spf13/cobra")
+}
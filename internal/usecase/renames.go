@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+// recordRenames records the renames detected while fetching repos, so that history
+// saved under the old names stays linked to the new ones. Failing to record them
+// doesn't fail the update: the renamed repositories are then only linked by later runs.
+func (u *Usecase) recordRenames(now time.Time, repos []*domain.Repository) {
+	var renamed []*domain.Repository
+	for _, repo := range repos {
+		if repo.Status == domain.StatusRenamed {
+			u.logger.Warn("Tracked repository was renamed, run 'repo renames --apply' to update the repository list", "from", repo.RenamedFrom, "to", repo.FullName)
+			renamed = append(renamed, repo)
+		}
+	}
+	if len(renamed) == 0 {
+		return
+	}
+
+	renames, err := u.storer.LoadRenames()
+	if err != nil {
+		u.logger.Warn("Failed to load recorded renames", "error", err)
+		return
+	}
+	changed := false
+	for _, repo := range renamed {
+		var added bool
+		renames, added = domain.RecordRename(renames, domain.Rename{From: repo.RenamedFrom, To: repo.FullName, ID: repo.ID, DetectedAt: now})
+		if added {
+			u.logger.Info("Recorded rename of repository", "from", repo.RenamedFrom, "to", repo.FullName)
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+	if err := u.storer.SaveRenames(renames); err != nil {
+		u.logger.Warn("Failed to save recorded renames", "error", err)
+	}
+}
+
+// loadRenameMap loads the recorded renames. When they can't be loaded,
+// history is only matched by name and an empty map is returned.
+func (u *Usecase) loadRenameMap() domain.RenameMap {
+	renames, err := u.storer.LoadRenames()
+	if err != nil {
+		u.logger.Warn("Failed to load recorded renames, matching history by name only", "error", err)
+	}
+	return domain.NewRenameMap(renames)
+}
+
+// ApplyRenames rewrites the entries of the repositories file that name a repository which was
+// since renamed or transferred, keeping their metadata. An entry whose new name is already
+// tracked is removed instead. It returns the entries that were rewritten or removed, as renames
+// from the entry to the new name. With dryRun, the repositories file is left untouched.
+func (u *Usecase) ApplyRenames(ctx context.Context, dryRun bool) ([]domain.Rename, error) {
+	targets, err := u.storer.LoadTargetRepos()
+	if err != nil {
+		u.logger.Error("Failed to load target repositories", "error", err)
+		return nil, fmt.Errorf("failed to load target repos: %w", err)
+	}
+	renames, err := u.storer.LoadRenames()
+	if err != nil {
+		u.logger.Error("Failed to load recorded renames", "error", err)
+		return nil, fmt.Errorf("failed to load renames: %w", err)
+	}
+	renameMap := domain.NewRenameMap(renames)
+
+	tracked := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		tracked[strings.ToLower(target.Name)] = true
+	}
+
+	var applied []domain.Rename
+	kept := make([]domain.TargetRepo, 0, len(targets))
+	for _, target := range targets {
+		if _, ok := target.Owner(); ok {
+			kept = append(kept, target)
+			continue
+		}
+		name := renameMap.ResolveKey(target.Name)
+		if name == strings.ToLower(target.Name) {
+			kept = append(kept, target)
+			continue
+		}
+		applied = append(applied, domain.Rename{From: target.Name, To: name})
+		if tracked[name] {
+			u.logger.Info("Renamed repository is already tracked under its new name, removing entry", "from", target.Name, "to", name)
+			continue
+		}
+		tracked[name] = true
+		target.Name = name
+		kept = append(kept, target)
+	}
+
+	if dryRun || len(applied) == 0 {
+		return applied, nil
+	}
+	if err := u.storer.SaveTargetRepos(kept); err != nil {
+		u.logger.Error("Failed to save target repositories", "error", err)
+		return nil, fmt.Errorf("failed to save target repos: %w", err)
+	}
+	u.logger.Info("Applied renames to the repository list", "count", len(applied))
+	return applied, nil
+}
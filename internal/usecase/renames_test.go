@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+)
+
+func TestUsecase_Update_RecordsRenames(t *testing.T) {
+	uc, fetcher, storer, _ := setupTestUsecase(t)
+
+	renamed, _ := domain.NewFetchedRepository("old/name", "new/name", 42, 100)
+	storer.On("LoadTargetRepos").Return(targets("old/name", "new/name"), nil).Once()
+	fetcher.On("FetchStars", mock.Anything, "old/name").Return(renamed, nil).Once()
+	fetcher.On("FetchStars", mock.Anything, "new/name").Return(renamed, nil).Once()
+	storer.On("LoadRenames").Return(nil, nil).Once()
+	storer.On("SaveRenames", mock.MatchedBy(func(renames []domain.Rename) bool {
+		return len(renames) == 1 && renames[0].From == "old/name" && renames[0].To == "new/name" && renames[0].ID == 42
+	})).Return(nil).Once()
+	// The repository tracked under both names is saved once, under its new name.
+	storer.On("Merge", mock.AnythingOfType("time.Time"), []*domain.Repository{renamed}).Return(nil).Once()
+
+	require.NoError(t, uc.Update(context.Background()))
+	storer.AssertExpectations(t)
+}
+
+func TestUsecase_Generate_LinksRenamedHistory(t *testing.T) {
+	uc, _, storer, cfg := setupTestUsecase(t)
+
+	today := time.Now().UTC()
+	pastDate := today.AddDate(0, 0, -7)
+
+	current, _ := domain.NewFetchedRepository("old/name", "new/name", 42, 100)
+	past, _ := domain.NewRepository("old/name", 70)
+
+	storer.On("Load", mock.MatchedBy(func(t time.Time) bool { return isSameDate(t, today) })).Return([]*domain.Repository{current}, nil).Once()
+	storer.On("Load", mock.MatchedBy(func(t time.Time) bool { return isSameDate(t, pastDate) })).Return([]*domain.Repository{past}, nil).Once()
+	storer.On("LoadTargetRepos").Return([]domain.TargetRepo{{Name: "old/name", DisplayName: "Renamed"}}, nil).Once()
+	storer.On("LoadRenames").Return([]domain.Rename{{From: "old/name", To: "new/name", ID: 42}}, nil).Once()
+
+	require.NoError(t, uc.Generate(context.Background()))
+
+	content, err := os.ReadFile(cfg.DashboardFilePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "[Renamed](https://github.com/new/name)")
+	assert.Contains(t, string(content), "30 ★") // 100 - 70, recorded under the old name
+}
+
+func TestUsecase_ApplyRenames(t *testing.T) {
+	setup := func(t *testing.T) (*Usecase, *MockStorer) {
+		uc, _, storer, _ := setupTestUsecase(t)
+		storer.On("LoadTargetRepos").Return([]domain.TargetRepo{
+			{Name: "a/old", Tags: []string{"web"}},
+			{Name: "b/old"},
+			{Name: "b/new"},
+			{Name: "org:c"},
+			{Name: "d/unchanged"},
+		}, nil).Once()
+		storer.On("LoadRenames").Return([]domain.Rename{
+			{From: "a/old", To: "A/New"},
+			{From: "b/old", To: "b/new"},
+		}, nil).Once()
+		return uc, storer
+	}
+	expected := []domain.Rename{{From: "a/old", To: "a/new"}, {From: "b/old", To: "b/new"}}
+
+	t.Run("Apply", func(t *testing.T) {
+		uc, storer := setup(t)
+		storer.On("SaveTargetRepos", []domain.TargetRepo{
+			{Name: "a/new", Tags: []string{"web"}},
+			{Name: "b/new"},
+			{Name: "org:c"},
+			{Name: "d/unchanged"},
+		}).Return(nil).Once()
+
+		applied, err := uc.ApplyRenames(context.Background(), false)
+		require.NoError(t, err)
+		assert.Equal(t, expected, applied)
+		storer.AssertExpectations(t)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		uc, storer := setup(t)
+
+		applied, err := uc.ApplyRenames(context.Background(), true)
+		require.NoError(t, err)
+		assert.Equal(t, expected, applied)
+		storer.AssertNotCalled(t, "SaveTargetRepos", mock.Anything)
+	})
+}
+
+func TestUsecase_Update_CarriesForwardRenamedHistory(t *testing.T) {
+	uc, fetcher, storer, cfg := setupTestUsecase(t)
+	cfg.CarryForward = true
+	cfg.CarryForwardMaxDays = 3
+
+	// The past snapshot predates IDs and still holds the repository under its old name.
+	past := &domain.Repository{FullName: "old/name", Stars: 80, Status: domain.StatusOK}
+	storer.On("LoadTargetRepos").Return(targets("new/name"), nil).Once()
+	fetcher.On("FetchStars", mock.Anything, "new/name").Return(nil, errors.New("fetch failed")).Once()
+	storer.On("LoadRange", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+		Return([]domain.Snapshot{{Date: time.Now().UTC().AddDate(0, 0, -1), Repositories: []*domain.Repository{past}}}, nil).Once()
+	storer.On("LoadRenames").Return([]domain.Rename{{From: "old/name", To: "new/name", ID: 42}}, nil).Once()
+	storer.On("Merge", mock.AnythingOfType("time.Time"), mock.MatchedBy(func(repos []*domain.Repository) bool {
+		return len(repos) == 1 && repos[0].FullName == "new/name" && repos[0].Stars == 80 && repos[0].Stale
+	})).Return(nil).Once()
+
+	require.NoError(t, uc.Update(context.Background()))
+	storer.AssertExpectations(t)
+}
@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+	"github.com/yourname/go-trendboard/internal/infra/github"
+	"github.com/yourname/go-trendboard/internal/infra/storage"
+)
+
+// RepoIssue is a problem found with an entry of the repositories file.
+type RepoIssue struct {
+	// RepoName is the entry as written in the repositories file.
+	RepoName string
+	// Problem describes what is wrong with the entry.
+	Problem string
+}
+
+// ListRepos returns the target repositories in the order of the repositories file.
+func (u *Usecase) ListRepos(ctx context.Context) ([]domain.TargetRepo, error) {
+	targets, err := u.storer.LoadTargetRepos()
+	if err != nil {
+		u.logger.Error("Failed to load target repositories", "error", err)
+		return nil, fmt.Errorf("failed to load target repos: %w", err)
+	}
+	return targets, nil
+}
+
+// AddRepos normalizes the given names and appends those not tracked yet to the repositories file,
+// which is created if it doesn't exist. Names may also be owner entries such as "org:<name>".
+// The new entries carry the given tags. With verify, every new repository must exist on GitHub
+// under the given name. It returns the names that were added. Nothing is saved if any name is invalid.
+func (u *Usecase) AddRepos(ctx context.Context, names, tags []string, verify bool) ([]string, error) {
+	targets, err := u.storer.LoadTargetRepos()
+	if err != nil && !errors.Is(err, storage.ErrReposConfigNotFound) {
+		u.logger.Error("Failed to load target repositories", "error", err)
+		return nil, fmt.Errorf("failed to load target repos: %w", err)
+	}
+
+	tracked := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		tracked[strings.ToLower(target.Name)] = true
+	}
+
+	var added []string
+	for _, input := range names {
+		name, err := domain.NormalizeTargetName(input)
+		if err != nil {
+			return nil, err
+		}
+		if tracked[name] {
+			u.logger.Info("Repository is already tracked, skipping", "repo", name)
+			continue
+		}
+		tracked[name] = true
+		added = append(added, name)
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	if verify {
+		if issues := u.verifyRepos(ctx, repoEntries(added)); len(issues) > 0 {
+			errs := make([]error, len(issues))
+			for i, issue := range issues {
+				errs[i] = fmt.Errorf("%s: %s", issue.RepoName, issue.Problem)
+			}
+			return nil, fmt.Errorf("failed to verify repositories: %w", errors.Join(errs...))
+		}
+	}
+
+	for _, name := range added {
+		target := domain.TargetRepo{Name: name}
+		target.AddTags(tags...)
+		targets = append(targets, target)
+	}
+	if err := u.storer.SaveTargetRepos(targets); err != nil {
+		u.logger.Error("Failed to save target repositories", "error", err)
+		return nil, fmt.Errorf("failed to save target repos: %w", err)
+	}
+	u.logger.Info("Added repositories", "count", len(added))
+	return added, nil
+}
+
+// RemoveRepos removes the given repositories from the repositories file, matching names case-insensitively.
+// It returns the entries that were removed. Nothing is saved if any name isn't tracked.
+func (u *Usecase) RemoveRepos(ctx context.Context, names []string) ([]string, error) {
+	targets, err := u.storer.LoadTargetRepos()
+	if err != nil {
+		u.logger.Error("Failed to load target repositories", "error", err)
+		return nil, fmt.Errorf("failed to load target repos: %w", err)
+	}
+
+	normalized := make([]string, len(names))
+	remove := make(map[string]bool, len(names))
+	for i, input := range names {
+		name, err := domain.NormalizeTargetName(input)
+		if err != nil {
+			return nil, err
+		}
+		normalized[i] = name
+		remove[name] = true
+	}
+
+	var kept []domain.TargetRepo
+	var removed []string
+	for _, target := range targets {
+		key := strings.ToLower(target.Name)
+		if remove[key] {
+			removed = append(removed, target.Name)
+			delete(remove, key)
+			continue
+		}
+		kept = append(kept, target)
+	}
+	if len(remove) > 0 {
+		var missing []string
+		for _, name := range normalized {
+			if remove[name] {
+				missing = append(missing, name)
+				delete(remove, name)
+			}
+		}
+		return nil, fmt.Errorf("repositories not tracked: %s", strings.Join(missing, ", "))
+	}
+
+	if err := u.storer.SaveTargetRepos(kept); err != nil {
+		u.logger.Error("Failed to save target repositories", "error", err)
+		return nil, fmt.Errorf("failed to save target repos: %w", err)
+	}
+	u.logger.Info("Removed repositories", "count", len(removed))
+	return removed, nil
+}
+
+// CheckRepos reports invalid, non-normalized and duplicate entries of the repositories file.
+// With verify, it also reports valid repository entries that can't be found on GitHub or were renamed.
+func (u *Usecase) CheckRepos(ctx context.Context, verify bool) ([]RepoIssue, error) {
+	targets, err := u.storer.LoadTargetRepos()
+	if err != nil {
+		u.logger.Error("Failed to load target repositories", "error", err)
+		return nil, fmt.Errorf("failed to load target repos: %w", err)
+	}
+
+	var issues []RepoIssue
+	var valid []string
+	seen := make(map[string]string, len(targets))
+	for _, target := range targets {
+		entry := target.Name
+		name, err := domain.NormalizeTargetName(entry)
+		if err != nil {
+			issues = append(issues, RepoIssue{RepoName: entry, Problem: err.Error()})
+			continue
+		}
+		if first, ok := seen[name]; ok {
+			issues = append(issues, RepoIssue{RepoName: entry, Problem: fmt.Sprintf("duplicate of '%s'", first)})
+			continue
+		}
+		seen[name] = entry
+		if name != entry {
+			issues = append(issues, RepoIssue{RepoName: entry, Problem: fmt.Sprintf("not normalized, should be '%s'", name)})
+		}
+		valid = append(valid, name)
+	}
+
+	if names := repoEntries(valid); verify && len(names) > 0 {
+		issues = append(issues, u.verifyRepos(ctx, names)...)
+	}
+	return issues, nil
+}
+
+// repoEntries returns the names that name a repository rather than an owner.
+func repoEntries(names []string) []string {
+	var repos []string
+	for _, name := range names {
+		if _, ok := domain.ParseOwner(name); !ok {
+			repos = append(repos, name)
+		}
+	}
+	return repos
+}
+
+// verifyRepos checks that the given repositories exist on GitHub.
+func (u *Usecase) verifyRepos(ctx context.Context, repoNames []string) []RepoIssue {
+	var issues []RepoIssue
+	for _, result := range u.fetchAll(ctx, repoNames) {
+		switch {
+		case result.Err == nil:
+			if result.Repository.Status == domain.StatusRenamed {
+				issues = append(issues, RepoIssue{RepoName: result.RepoName, Problem: fmt.Sprintf("renamed to '%s'", result.Repository.FullName)})
+			}
+		case errors.Is(result.Err, github.ErrNotFound):
+			issues = append(issues, RepoIssue{RepoName: result.RepoName, Problem: "not found on GitHub"})
+		default:
+			issues = append(issues, RepoIssue{RepoName: result.RepoName, Problem: fmt.Sprintf("could not be verified: %v", result.Err)})
+		}
+	}
+	return issues
+}
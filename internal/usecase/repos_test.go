@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourname/go-trendboard/internal/domain"
+	"github.com/yourname/go-trendboard/internal/infra/github"
+	"github.com/yourname/go-trendboard/internal/infra/storage"
+)
+
+// notFoundError is a fetch error that matches github.ErrNotFound.
+type notFoundError struct{}
+
+func (notFoundError) Error() string        { return "not found" }
+func (notFoundError) Is(target error) bool { return target == github.ErrNotFound }
+
+func TestUsecase_AddRepos(t *testing.T) {
+	t.Run("Normalizes and deduplicates", func(t *testing.T) {
+		uc, _, storer, _ := setupTestUsecase(t)
+		storer.On("LoadTargetRepos").Return(targets("gin-gonic/gin"), nil).Once()
+		storer.On("SaveTargetRepos", targets("gin-gonic/gin", "spf13/cobra")).Return(nil).Once()
+
+		added, err := uc.AddRepos(context.Background(), []string{"Gin-Gonic/Gin", "https://github.com/spf13/cobra.git", "spf13/COBRA"}, nil, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"spf13/cobra"}, added)
+		storer.AssertExpectations(t)
+	})
+
+	t.Run("Creates the repositories file", func(t *testing.T) {
+		uc, _, storer, _ := setupTestUsecase(t)
+		storer.On("LoadTargetRepos").Return(nil, storage.ErrReposConfigNotFound).Once()
+		storer.On("SaveTargetRepos", targets("owner/repo")).Return(nil).Once()
+
+		_, err := uc.AddRepos(context.Background(), []string{"owner/repo"}, nil, false)
+		require.NoError(t, err)
+		storer.AssertExpectations(t)
+	})
+
+	t.Run("Invalid name", func(t *testing.T) {
+		uc, _, storer, _ := setupTestUsecase(t)
+		storer.On("LoadTargetRepos").Return(targets(), nil).Once()
+
+		_, err := uc.AddRepos(context.Background(), []string{"owner/repo", "invalid"}, nil, false)
+		require.Error(t, err)
+		storer.AssertNotCalled(t, "SaveTargetRepos", mock.Anything)
+	})
+
+	t.Run("Verify rejects missing repositories", func(t *testing.T) {
+		uc, fetcher, storer, _ := setupTestUsecase(t)
+		storer.On("LoadTargetRepos").Return(targets(), nil).Once()
+		repo, _ := domain.NewRepository("owner/repo", 1)
+		fetcher.On("FetchStars", mock.Anything, "owner/repo").Return(repo, nil).Once()
+		fetcher.On("FetchStars", mock.Anything, "owner/missing").Return(nil, notFoundError{}).Once()
+
+		_, err := uc.AddRepos(context.Background(), []string{"owner/repo", "owner/missing"}, nil, true)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "owner/missing: not found on GitHub")
+		storer.AssertNotCalled(t, "SaveTargetRepos", mock.Anything)
+	})
+}
+
+func TestUsecase_RemoveRepos(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		uc, _, storer, _ := setupTestUsecase(t)
+		storer.On("LoadTargetRepos").Return(targets("gin-gonic/gin", "BurntSushi/toml", "spf13/cobra"), nil).Once()
+		storer.On("SaveTargetRepos", targets("gin-gonic/gin")).Return(nil).Once()
+
+		removed, err := uc.RemoveRepos(context.Background(), []string{"burntsushi/toml", "spf13/cobra"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"BurntSushi/toml", "spf13/cobra"}, removed)
+		storer.AssertExpectations(t)
+	})
+
+	t.Run("Not tracked", func(t *testing.T) {
+		uc, _, storer, _ := setupTestUsecase(t)
+		storer.On("LoadTargetRepos").Return(targets("gin-gonic/gin"), nil).Once()
+
+		_, err := uc.RemoveRepos(context.Background(), []string{"gin-gonic/gin", "owner/other"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "owner/other")
+		storer.AssertNotCalled(t, "SaveTargetRepos", mock.Anything)
+	})
+}
+
+func TestUsecase_CheckRepos(t *testing.T) {
+	uc, fetcher, storer, _ := setupTestUsecase(t)
+	storer.On("LoadTargetRepos").Return(targets("owner/repo", "invalid", "Owner/Repo", "Owner/Other", "owner/gone"), nil)
+
+	issues, err := uc.CheckRepos(context.Background(), false)
+	require.NoError(t, err)
+	assert.Equal(t, []RepoIssue{
+		{RepoName: "invalid", Problem: "invalid repository full name format: invalid"},
+		{RepoName: "Owner/Repo", Problem: "duplicate of 'owner/repo'"},
+		{RepoName: "Owner/Other", Problem: "not normalized, should be 'owner/other'"},
+	}, issues)
+
+	repo, _ := domain.NewRepository("owner/repo", 1)
+	fetcher.On("FetchStars", mock.Anything, "owner/repo").Return(repo, nil).Once()
+	renamed, _ := domain.NewFetchedRepository("owner/other", "owner/another", 0, 1)
+	fetcher.On("FetchStars", mock.Anything, "owner/other").Return(renamed, nil).Once()
+	fetcher.On("FetchStars", mock.Anything, "owner/gone").Return(nil, notFoundError{}).Once()
+
+	issues, err = uc.CheckRepos(context.Background(), true)
+	require.NoError(t, err)
+	require.Len(t, issues, 5)
+	assert.Equal(t, RepoIssue{RepoName: "owner/other", Problem: "renamed to 'owner/another'"}, issues[3])
+	assert.Equal(t, RepoIssue{RepoName: "owner/gone", Problem: "not found on GitHub"}, issues[4])
+	fetcher.AssertExpectations(t)
+
+	storer.On("LoadTargetRepos").Unset()
+	storer.On("LoadTargetRepos").Return(nil, errors.New("disk error"))
+	_, err = uc.CheckRepos(context.Background(), false)
+	require.Error(t, err)
+}
@@ -2,9 +2,11 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"time"
 
 	"golang.org/x/sync/errgroup"
@@ -18,19 +20,19 @@ import (
 
 // Usecase handles the main business logic of the application.
 type Usecase struct {
-	cfg       *config.Config
-	logger    *slog.Logger
-	fetcher   github.Fetcher
-	storer    storage.Storer
+	cfg     *config.Config
+	logger  *slog.Logger
+	fetcher github.Fetcher
+	storer  storage.Storer
 }
 
 // NewUsecase creates a new Usecase.
 func NewUsecase(cfg *config.Config, logger *slog.Logger, fetcher github.Fetcher, storer storage.Storer) *Usecase {
 	return &Usecase{
-		cfg:       cfg,
-		logger:    logger.With("component", "usecase"),
-		fetcher:   fetcher,
-		storer:    storer,
+		cfg:     cfg,
+		logger:  logger.With("component", "usecase"),
+		fetcher: fetcher,
+		storer:  storer,
 	}
 }
 
@@ -44,15 +46,15 @@ func (u *Usecase) Initialize(ctx context.Context) error {
 		return nil
 	}
 
-	defaultRepos := []string{
-		"gin-gonic/gin",
-		"go-chi/chi",
-		"gorm/gorm",
-		"spf13/cobra",
-		"stretchr/testify",
-		"uber-go/zap",
-		"golang/mock",
-		"google/go-github",
+	defaultRepos := []domain.TargetRepo{
+		{Name: "gin-gonic/gin"},
+		{Name: "go-chi/chi"},
+		{Name: "gorm/gorm"},
+		{Name: "spf13/cobra"},
+		{Name: "stretchr/testify"},
+		{Name: "uber-go/zap"},
+		{Name: "golang/mock"},
+		{Name: "google/go-github"},
 	}
 
 	if err := u.storer.SaveTargetRepos(defaultRepos); err != nil {
@@ -68,59 +70,134 @@ func (u *Usecase) Initialize(ctx context.Context) error {
 func (u *Usecase) Update(ctx context.Context) error {
 	u.logger.Info("Updating repository data...")
 
-	targetRepos, err := u.storer.LoadTargetRepos()
+	targets, err := u.storer.LoadTargetRepos()
 	if err != nil {
 		u.logger.Error("Failed to load target repositories", "error", err)
 		return fmt.Errorf("failed to load target repositories: %w", err)
 	}
+	targetRepos := u.expandTargets(ctx, targets)
 
-	g, gCtx := errgroup.WithContext(ctx)
-	g.SetLimit(8) // Limit concurrency to avoid hitting rate limits too quickly.
-
-	results := make(chan *domain.Repository, len(targetRepos))
-
-	for _, repoName := range targetRepos {
-		repoName := repoName // https://golang.org/doc/faq#closures_and_goroutines
-		g.Go(func() error {
-			repo, err := u.fetcher.FetchStars(gCtx, repoName)
-			if err != nil {
-				// Log the error but don't fail the entire update.
-				// This allows the process to continue even if one repo is unavailable.
-				u.logger.Warn("Failed to fetch stars for repository", "repo", repoName, "error", err)
-				return nil
-			}
-			results <- repo
-			return nil
-		})
-	}
-
-	if err := g.Wait(); err != nil {
-		// This should ideally not happen since we are returning nil in goroutines.
-		u.logger.Error("Error during concurrent fetching", "error", err)
-		// We can choose to continue or fail here. Let's continue.
+	if !u.cfg.HasCredentials() {
+		if len(targetRepos) > github.UnauthenticatedRateLimit {
+			u.logger.Error("Too many repositories for unauthenticated access", "count", len(targetRepos), "limit", github.UnauthenticatedRateLimit)
+			return fmt.Errorf("%d repositories exceed the unauthenticated limit of %d requests per hour: set GITHUB_TOKEN", len(targetRepos), github.UnauthenticatedRateLimit)
+		}
+		u.logger.Warn("Fetching without credentials; GitHub allows only 60 requests per hour per IP address", "count", len(targetRepos))
 	}
-	close(results)
 
+	today := time.Now().UTC()
+	fetchedAt := today.Truncate(time.Second)
 	updatedRepos := make([]*domain.Repository, 0, len(targetRepos))
-	for repo := range results {
-		updatedRepos = append(updatedRepos, repo)
+	var failedRepos []*domain.Repository
+	fetched := make(map[string]bool, len(targetRepos))
+	for _, result := range u.fetchAll(ctx, targetRepos) {
+		if result.Err != nil {
+			// Log the error but don't fail the entire update.
+			// This allows the process to continue even if one repo is unavailable.
+			u.logger.Warn("Failed to fetch stars for repository", "repo", result.RepoName, "error", result.Err)
+			status := domain.StatusFailed
+			if errors.Is(result.Err, github.ErrNotFound) {
+				status = domain.StatusNotFound
+			}
+			failedRepos = append(failedRepos, domain.NewFailedRepository(result.RepoName, status))
+			continue
+		}
+		// A renamed repository tracked under both its old and new names is fetched twice.
+		if fetched[result.Repository.Key()] {
+			u.logger.Debug("Skipping repository fetched under another name", "repo", result.RepoName, "canonical", result.Repository.FullName)
+			continue
+		}
+		fetched[result.Repository.Key()] = true
+		result.Repository.FetchedAt = fetchedAt
+		if u.cfg.IntradaySamples {
+			result.Repository.Samples = []domain.Sample{{FetchedAt: fetchedAt, Stars: result.Repository.Stars}}
+		}
+		updatedRepos = append(updatedRepos, result.Repository)
 	}
+	u.recordRenames(today, updatedRepos)
 
+	if len(failedRepos) > 0 && u.cfg.CarryForward {
+		u.carryForward(today, failedRepos)
+	}
 	if len(updatedRepos) == 0 {
 		u.logger.Warn("No repository data was successfully updated.")
-		return nil
+		if len(failedRepos) == 0 {
+			return nil
+		}
 	}
+	successCount := len(updatedRepos)
+	updatedRepos = append(updatedRepos, failedRepos...)
 
-	today := time.Now().UTC()
-	if err := u.storer.Save(today, updatedRepos); err != nil {
+	// An earlier run of the day may have fetched repositories this one failed to, so merge rather than overwrite.
+	if err := u.storer.Merge(today, updatedRepos); err != nil {
 		u.logger.Error("Failed to save updated repository data", "error", err)
 		return fmt.Errorf("failed to save updated data: %w", err)
 	}
 
-	u.logger.Info("Successfully updated repository data.", "count", len(updatedRepos))
+	u.logger.Info("Successfully updated repository data.", "count", successCount, "failed", len(failedRepos))
 	return nil
 }
 
+// carryForward fills the failed repositories with their last known star count,
+// looking back at most CarryForwardMaxDays days from today. Past entries are matched
+// like Generate matches them, so renamed repositories keep their history.
+func (u *Usecase) carryForward(today time.Time, failedRepos []*domain.Repository) {
+	snapshots, err := u.storer.LoadRange(today.AddDate(0, 0, -u.cfg.CarryForwardMaxDays), today.AddDate(0, 0, -1))
+	if err != nil {
+		u.logger.Warn("Failed to load past data for carry-forward", "error", err)
+	}
+	var renames domain.RenameMap
+	if len(snapshots) > 0 {
+		renames = u.loadRenameMap()
+	}
+
+	pending := slices.Clone(failedRepos)
+	// Snapshots are in ascending order; the most recent value wins.
+	for i := len(snapshots) - 1; i >= 0 && len(pending) > 0; i-- {
+		date := snapshots[i].Date
+		index := domain.NewRepositoryIndex(snapshots[i].Repositories, renames)
+		pending = slices.DeleteFunc(pending, func(repo *domain.Repository) bool {
+			pastRepo, ok := index.Find(repo)
+			if !ok || !pastRepo.HasStars() {
+				return false
+			}
+			repo.CarryForward(pastRepo)
+			u.logger.Info("Carried forward last known star count", "repo", repo.FullName, "stars", repo.Stars, "date", date.Format("2006-01-02"))
+			return true
+		})
+	}
+
+	for _, repo := range pending {
+		u.logger.Warn("No last known star count to carry forward", "repo", repo.FullName)
+	}
+}
+
+// fetchAll fetches the given repositories, using a single batched call when the
+// fetcher supports it and bounded concurrent calls otherwise.
+func (u *Usecase) fetchAll(ctx context.Context, repoNames []string) []github.FetchResult {
+	if bf, ok := u.fetcher.(github.BatchFetcher); ok {
+		return bf.FetchStarsBatch(ctx, repoNames)
+	}
+
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(8) // Limit concurrency to avoid hitting rate limits too quickly.
+
+	results := make([]github.FetchResult, len(repoNames))
+	for i, repoName := range repoNames {
+		g.Go(func() error {
+			repo, err := u.fetcher.FetchStars(gCtx, repoName)
+			results[i] = github.FetchResult{RepoName: repoName, Repository: repo, Err: err}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		// This should ideally not happen since we are returning nil in goroutines.
+		u.logger.Error("Error during concurrent fetching", "error", err)
+	}
+	return results
+}
+
 // Generate creates a dashboard file based on historical data.
 func (u *Usecase) Generate(ctx context.Context) error {
 	u.logger.Info("Generating trend dashboard...")
@@ -143,16 +220,42 @@ func (u *Usecase) Generate(ctx context.Context) error {
 		// We can continue without past data, the trend will be the full star count.
 	}
 
-	pastDataMap := make(map[string]int, len(pastData))
-	for _, repo := range pastData {
-		pastDataMap[repo.FullName] = repo.Stars
+	// Repositories are matched across snapshots by GitHub ID, or by name following renames
+	// for snapshots taken before IDs were recorded.
+	renames := u.loadRenameMap()
+	pastIndex := domain.NewRepositoryIndex(pastData, renames)
+
+	// Display names and tags come from the tracked list, repositories of owner entries inheriting the
+	// owner entry's tags; repositories no longer listed keep their full name.
+	targets, err := u.storer.LoadTargetRepos()
+	if err != nil {
+		u.logger.Warn("Failed to load target repositories, rendering without their metadata", "error", err)
+	}
+	for i := range targets {
+		if _, ok := targets[i].Owner(); !ok {
+			targets[i].Name = renames.Resolve(targets[i].Name)
+		}
+	}
+	repoNames := make([]string, len(todayData))
+	for i, repo := range todayData {
+		repoNames[i] = repo.FullName
 	}
+	targetMap := targetsByRepo(targets, repoNames)
 
 	trends := make([]*domain.Trend, 0, len(todayData))
 	for _, repo := range todayData {
-		pastStars := pastDataMap[repo.FullName] // Defaults to 0 if not found
+		if !repo.HasStars() {
+			u.logger.Warn("Skipping repository without a known star count", "repo", repo.FullName, "status", repo.Status)
+			continue
+		}
+		pastStars := 0 // Compared against 0 when there's no usable past value.
+		if past, ok := pastIndex.Find(repo); ok && past.HasStars() {
+			pastStars = past.Stars
+		}
 		diff := repo.Stars - pastStars
-		trends = append(trends, domain.NewTrend(repo, diff, period))
+		trend := domain.NewTrend(repo, diff, period)
+		trend.Target = targetMap[renames.ResolveKey(repo.FullName)]
+		trends = append(trends, trend)
 	}
 
 	domain.SortTrends(trends)
@@ -170,7 +273,8 @@ func (u *Usecase) Generate(ctx context.Context) error {
 		return err // Already logged in presenter factory
 	}
 
-	if err := p.Render(file, trends); err != nil {
+	dashboard := presenter.NewDashboard(period, trends, u.cfg.DashboardCategories)
+	if err := p.Render(file, dashboard); err != nil {
 		// Already logged in presenter
 		return fmt.Errorf("failed to render dashboard: %w", err)
 	}
@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
@@ -15,6 +16,8 @@ import (
 
 	"github.com/yourname/go-trendboard/internal/config"
 	"github.com/yourname/go-trendboard/internal/domain"
+	"github.com/yourname/go-trendboard/internal/infra/github"
+	"github.com/yourname/go-trendboard/internal/infra/storage"
 )
 
 // --- Mocks ---
@@ -31,6 +34,15 @@ func (m *MockFetcher) FetchStars(ctx context.Context, repoName string) (*domain.
 	return args.Get(0).(*domain.Repository), args.Error(1)
 }
 
+type MockBatchFetcher struct {
+	MockFetcher
+}
+
+func (m *MockBatchFetcher) FetchStarsBatch(ctx context.Context, repoNames []string) []github.FetchResult {
+	args := m.Called(ctx, repoNames)
+	return args.Get(0).([]github.FetchResult)
+}
+
 type MockStorer struct {
 	mock.Mock
 }
@@ -40,6 +52,11 @@ func (m *MockStorer) Save(date time.Time, repos []*domain.Repository) error {
 	return args.Error(0)
 }
 
+func (m *MockStorer) Merge(date time.Time, repos []*domain.Repository) error {
+	args := m.Called(date, repos)
+	return args.Error(0)
+}
+
 func (m *MockStorer) Load(date time.Time) ([]*domain.Repository, error) {
 	args := m.Called(date)
 	if args.Get(0) == nil {
@@ -48,19 +65,73 @@ func (m *MockStorer) Load(date time.Time) ([]*domain.Repository, error) {
 	return args.Get(0).([]*domain.Repository), args.Error(1)
 }
 
-func (m *MockStorer) LoadTargetRepos() ([]string, error) {
+func (m *MockStorer) LoadRange(from, to time.Time) ([]domain.Snapshot, error) {
+	args := m.Called(from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Snapshot), args.Error(1)
+}
+
+func (m *MockStorer) LoadSeries(repoName string, from, to time.Time) ([]domain.SeriesPoint, error) {
+	args := m.Called(repoName, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.SeriesPoint), args.Error(1)
+}
+
+func (m *MockStorer) LoadLatest() (*domain.Snapshot, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Snapshot), args.Error(1)
+}
+
+func (m *MockStorer) ListDates() ([]time.Time, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]time.Time), args.Error(1)
+}
+
+func (m *MockStorer) LoadTargetRepos() ([]domain.TargetRepo, error) {
 	args := m.Called()
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).([]string), args.Error(1)
+	return args.Get(0).([]domain.TargetRepo), args.Error(1)
 }
 
-func (m *MockStorer) SaveTargetRepos(repos []string) error {
+func (m *MockStorer) SaveTargetRepos(repos []domain.TargetRepo) error {
 	args := m.Called(repos)
 	return args.Error(0)
 }
 
+func (m *MockStorer) LoadRenames() ([]domain.Rename, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.Rename), args.Error(1)
+}
+
+func (m *MockStorer) SaveRenames(renames []domain.Rename) error {
+	args := m.Called(renames)
+	return args.Error(0)
+}
+
+// targets returns plain target repository entries for the given names.
+func targets(names ...string) []domain.TargetRepo {
+	targets := make([]domain.TargetRepo, len(names))
+	for i, name := range names {
+		targets[i] = domain.TargetRepo{Name: name}
+	}
+	return targets
+}
+
 // --- Tests ---
 
 func setupTestUsecase(t *testing.T) (*Usecase, *MockFetcher, *MockStorer, *config.Config) {
@@ -71,6 +142,7 @@ func setupTestUsecase(t *testing.T) (*Usecase, *MockFetcher, *MockStorer, *confi
 	
 	tempDir := t.TempDir()
 	cfg := &config.Config{
+		GitHubToken:         "test-token",
 		ReposFilePath:       filepath.Join(tempDir, "repos.json"),
 		DashboardFilePath:   filepath.Join(tempDir, "dashboard.md"),
 		DashboardTemplatePath: filepath.Join(tempDir, "dashboard.tpl"),
@@ -84,7 +156,7 @@ func setupTestUsecase(t *testing.T) (*Usecase, *MockFetcher, *MockStorer, *confi
 func TestUsecase_Initialize(t *testing.T) {
 	t.Run("File does not exist", func(t *testing.T) {
 		uc, _, storer, _ := setupTestUsecase(t)
-		storer.On("SaveTargetRepos", mock.AnythingOfType("[]string")).Return(nil).Once()
+		storer.On("SaveTargetRepos", mock.AnythingOfType("[]domain.TargetRepo")).Return(nil).Once()
 		
 		err := uc.Initialize(context.Background())
 		require.NoError(t, err)
@@ -111,10 +183,10 @@ func TestUsecase_Update(t *testing.T) {
 	repo1, _ := domain.NewRepository("owner/repo1", 100)
 	repo2, _ := domain.NewRepository("owner/repo2", 200)
 
-	storer.On("LoadTargetRepos").Return(targetRepos, nil).Once()
+	storer.On("LoadTargetRepos").Return(targets(targetRepos...), nil).Once()
 	fetcher.On("FetchStars", mock.Anything, "owner/repo1").Return(repo1, nil).Once()
 	fetcher.On("FetchStars", mock.Anything, "owner/repo2").Return(repo2, nil).Once()
-	storer.On("Save", mock.AnythingOfType("time.Time"), mock.AnythingOfType("[]*domain.Repository")).Return(nil).Once()
+	storer.On("Merge", mock.AnythingOfType("time.Time"), mock.AnythingOfType("[]*domain.Repository")).Return(nil).Once()
 
 	err := uc.Update(context.Background())
 	require.NoError(t, err)
@@ -123,19 +195,67 @@ func TestUsecase_Update(t *testing.T) {
 	storer.AssertExpectations(t)
 }
 
+func TestUsecase_Update_SkipsDisabled(t *testing.T) {
+	uc, fetcher, storer, _ := setupTestUsecase(t)
+
+	repo1, _ := domain.NewRepository("owner/repo1", 100)
+	storer.On("LoadTargetRepos").Return([]domain.TargetRepo{{Name: "owner/repo1"}, {Name: "owner/repo2", Disabled: true}}, nil).Once()
+	fetcher.On("FetchStars", mock.Anything, "owner/repo1").Return(repo1, nil).Once()
+	storer.On("Merge", mock.AnythingOfType("time.Time"), []*domain.Repository{repo1}).Return(nil).Once()
+
+	require.NoError(t, uc.Update(context.Background()))
+	fetcher.AssertExpectations(t)
+	fetcher.AssertNotCalled(t, "FetchStars", mock.Anything, "owner/repo2")
+	storer.AssertExpectations(t)
+}
+
+func TestUsecase_Update_Unauthenticated(t *testing.T) {
+	t.Run("Small list", func(t *testing.T) {
+		uc, fetcher, storer, cfg := setupTestUsecase(t)
+		cfg.GitHubToken = ""
+
+		repo1, _ := domain.NewRepository("owner/repo1", 100)
+		storer.On("LoadTargetRepos").Return(targets("owner/repo1"), nil).Once()
+		fetcher.On("FetchStars", mock.Anything, "owner/repo1").Return(repo1, nil).Once()
+		storer.On("Merge", mock.AnythingOfType("time.Time"), mock.AnythingOfType("[]*domain.Repository")).Return(nil).Once()
+
+		require.NoError(t, uc.Update(context.Background()))
+		storer.AssertExpectations(t)
+	})
+
+	t.Run("List exceeds unauthenticated limit", func(t *testing.T) {
+		uc, fetcher, storer, cfg := setupTestUsecase(t)
+		cfg.GitHubToken = ""
+
+		targetRepos := make([]string, github.UnauthenticatedRateLimit+1)
+		for i := range targetRepos {
+			targetRepos[i] = fmt.Sprintf("owner/repo%d", i)
+		}
+		storer.On("LoadTargetRepos").Return(targets(targetRepos...), nil).Once()
+
+		err := uc.Update(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unauthenticated limit")
+		fetcher.AssertNotCalled(t, "FetchStars", mock.Anything, mock.Anything)
+		storer.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything)
+	})
+}
+
 func TestUsecase_Update_FetchFailure(t *testing.T) {
 	uc, fetcher, storer, _ := setupTestUsecase(t)
 
 	targetRepos := []string{"owner/repo1", "owner/repo2"}
 	repo2, _ := domain.NewRepository("owner/repo2", 200)
 
-	storer.On("LoadTargetRepos").Return(targetRepos, nil).Once()
+	storer.On("LoadTargetRepos").Return(targets(targetRepos...), nil).Once()
 	fetcher.On("FetchStars", mock.Anything, "owner/repo1").Return(nil, errors.New("fetch failed")).Once()
 	fetcher.On("FetchStars", mock.Anything, "owner/repo2").Return(repo2, nil).Once()
 	
-	// Check that save is still called with the successfully fetched repo
-	storer.On("Save", mock.AnythingOfType("time.Time"), mock.MatchedBy(func(repos []*domain.Repository) bool {
-		return len(repos) == 1 && repos[0].FullName == "owner/repo2"
+	// Check that save is still called with the successfully fetched repo, and the failure is recorded
+	storer.On("Merge", mock.AnythingOfType("time.Time"), mock.MatchedBy(func(repos []*domain.Repository) bool {
+		return len(repos) == 2 &&
+			repos[0].FullName == "owner/repo2" && repos[0].Status == domain.StatusOK &&
+			repos[1].FullName == "owner/repo1" && repos[1].Status == domain.StatusFailed && !repos[1].HasStars()
 	})).Return(nil).Once()
 	
 	err := uc.Update(context.Background())
@@ -145,6 +265,107 @@ func TestUsecase_Update_FetchFailure(t *testing.T) {
 	storer.AssertExpectations(t)
 }
 
+func TestUsecase_Update_CarryForward(t *testing.T) {
+	uc, fetcher, storer, cfg := setupTestUsecase(t)
+	cfg.CarryForward = true
+	cfg.CarryForwardMaxDays = 3
+
+	today := time.Now().UTC()
+	targetRepos := []string{"owner/repo1", "owner/repo2", "owner/repo3"}
+	repo1, _ := domain.NewRepository("owner/repo1", 100)
+	repo2Past, _ := domain.NewRepository("owner/repo2", 80)
+
+	storer.On("LoadTargetRepos").Return(targets(targetRepos...), nil).Once()
+	fetcher.On("FetchStars", mock.Anything, "owner/repo1").Return(repo1, nil).Once()
+	fetcher.On("FetchStars", mock.Anything, "owner/repo2").Return(nil, errors.New("fetch failed")).Once()
+	fetcher.On("FetchStars", mock.Anything, "owner/repo3").Return(nil, &github.FetchError{}).Once()
+	// The value of two days ago is more recent than the one of three days ago.
+	repo2Older, _ := domain.NewRepository("owner/repo2", 70)
+	storer.On("LoadRange",
+		mock.MatchedBy(func(t time.Time) bool { return isSameDate(t, today.AddDate(0, 0, -3)) }),
+		mock.MatchedBy(func(t time.Time) bool { return isSameDate(t, today.AddDate(0, 0, -1)) }),
+	).Return([]domain.Snapshot{
+		{Date: today.AddDate(0, 0, -3), Repositories: []*domain.Repository{repo2Older}},
+		{Date: today.AddDate(0, 0, -2), Repositories: []*domain.Repository{repo2Past}},
+	}, nil).Once()
+	storer.On("LoadRenames").Return(nil, nil).Once()
+
+	var saved []*domain.Repository
+	storer.On("Merge", mock.AnythingOfType("time.Time"), mock.AnythingOfType("[]*domain.Repository")).
+		Run(func(args mock.Arguments) { saved = args.Get(1).([]*domain.Repository) }).
+		Return(nil).Once()
+
+	err := uc.Update(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, saved, 3)
+	assert.Equal(t, "owner/repo2", saved[1].FullName)
+	assert.Equal(t, domain.StatusFailed, saved[1].Status)
+	assert.Equal(t, 80, saved[1].Stars)
+	assert.True(t, saved[1].Stale)
+	assert.Equal(t, "owner/repo3", saved[2].FullName)
+	assert.False(t, saved[2].HasStars())
+
+	fetcher.AssertExpectations(t)
+	storer.AssertExpectations(t)
+}
+
+func TestUsecase_Update_AllFailed(t *testing.T) {
+	uc, fetcher, storer, cfg := setupTestUsecase(t)
+	cfg.CarryForward = true
+	cfg.CarryForwardMaxDays = 3
+
+	repo1Past, _ := domain.NewRepository("owner/repo1", 80)
+	storer.On("LoadTargetRepos").Return(targets("owner/repo1", "owner/repo2"), nil).Once()
+	fetcher.On("FetchStars", mock.Anything, "owner/repo1").Return(nil, errors.New("fetch failed")).Once()
+	fetcher.On("FetchStars", mock.Anything, "owner/repo2").Return(nil, github.ErrNotFound).Once()
+	storer.On("LoadRange", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).
+		Return([]domain.Snapshot{{Date: time.Now().UTC().AddDate(0, 0, -1), Repositories: []*domain.Repository{repo1Past}}}, nil).Once()
+	storer.On("LoadRenames").Return(nil, nil).Once()
+
+	// The failures are still saved, with the last known star count carried forward.
+	var saved []*domain.Repository
+	storer.On("Merge", mock.AnythingOfType("time.Time"), mock.AnythingOfType("[]*domain.Repository")).
+		Run(func(args mock.Arguments) { saved = args.Get(1).([]*domain.Repository) }).
+		Return(nil).Once()
+
+	require.NoError(t, uc.Update(context.Background()))
+
+	require.Len(t, saved, 2)
+	assert.Equal(t, "owner/repo1", saved[0].FullName)
+	assert.Equal(t, domain.StatusFailed, saved[0].Status)
+	assert.Equal(t, 80, saved[0].Stars)
+	assert.True(t, saved[0].Stale)
+	assert.Equal(t, "owner/repo2", saved[1].FullName)
+	assert.Equal(t, domain.StatusNotFound, saved[1].Status)
+	fetcher.AssertExpectations(t)
+	storer.AssertExpectations(t)
+}
+
+func TestUsecase_Update_BatchFetcher(t *testing.T) {
+	uc, _, storer, _ := setupTestUsecase(t)
+	fetcher := new(MockBatchFetcher)
+	uc.fetcher = fetcher
+
+	targetRepos := []string{"owner/repo1", "owner/repo2"}
+	repo1, _ := domain.NewRepository("owner/repo1", 100)
+
+	storer.On("LoadTargetRepos").Return(targets(targetRepos...), nil).Once()
+	fetcher.On("FetchStarsBatch", mock.Anything, targetRepos).Return([]github.FetchResult{
+		{RepoName: "owner/repo1", Repository: repo1},
+		{RepoName: "owner/repo2", Err: errors.New("fetch failed")},
+	}).Once()
+	storer.On("Merge", mock.AnythingOfType("time.Time"), mock.MatchedBy(func(repos []*domain.Repository) bool {
+		return len(repos) == 2 && repos[0].FullName == "owner/repo1" && repos[1].Status == domain.StatusFailed
+	})).Return(nil).Once()
+
+	err := uc.Update(context.Background())
+	require.NoError(t, err)
+
+	fetcher.AssertExpectations(t)
+	fetcher.AssertNotCalled(t, "FetchStars", mock.Anything, mock.Anything)
+	storer.AssertExpectations(t)
+}
 
 func TestUsecase_Generate(t *testing.T) {
 	uc, _, storer, cfg := setupTestUsecase(t)
@@ -159,6 +380,8 @@ func TestUsecase_Generate(t *testing.T) {
 
 	storer.On("Load", mock.MatchedBy(func(t time.Time) bool { return isSameDate(t, today) })).Return(todayData, nil).Once()
 	storer.On("Load", mock.MatchedBy(func(t time.Time) bool { return isSameDate(t, pastDate) })).Return(pastData, nil).Once()
+	storer.On("LoadTargetRepos").Return([]domain.TargetRepo{{Name: "Owner/Repo1", DisplayName: "Repo One"}}, nil).Once()
+	storer.On("LoadRenames").Return(nil, nil).Once()
 
 	err := uc.Generate(context.Background())
 	require.NoError(t, err)
@@ -167,13 +390,63 @@ func TestUsecase_Generate(t *testing.T) {
 	content, err := os.ReadFile(cfg.DashboardFilePath)
 	require.NoError(t, err)
 	assert.NotEmpty(t, content)
-	assert.Contains(t, string(content), "owner/repo1")
+	assert.Contains(t, string(content), "[Repo One](https://github.com/owner/repo1)")
 	assert.Contains(t, string(content), "20 ★") // 100 - 80
 
 	storer.AssertExpectations(t)
 }
 
+func TestUsecase_Generate_SkipsUnknownStars(t *testing.T) {
+	uc, _, storer, cfg := setupTestUsecase(t)
+
+	today := time.Now().UTC()
+	pastDate := today.AddDate(0, 0, -7)
+
+	repo1, _ := domain.NewRepository("owner/repo1", 100)
+	repo2 := domain.NewFailedRepository("owner/repo2", domain.StatusNotFound)
+	repo3 := domain.NewFailedRepository("owner/repo3", domain.StatusFailed)
+	repo3.CarryForward(&domain.Repository{Stars: 300})
+	repo1Past := domain.NewFailedRepository("owner/repo1", domain.StatusFailed)
+	repo3Past, _ := domain.NewRepository("owner/repo3", 290)
+
+	storer.On("Load", mock.MatchedBy(func(t time.Time) bool { return isSameDate(t, today) })).Return([]*domain.Repository{repo1, repo2, repo3}, nil).Once()
+	storer.On("Load", mock.MatchedBy(func(t time.Time) bool { return isSameDate(t, pastDate) })).Return([]*domain.Repository{repo1Past, repo3Past}, nil).Once()
+	storer.On("LoadTargetRepos").Return(nil, storage.ErrReposConfigNotFound).Once()
+	storer.On("LoadRenames").Return(nil, nil).Once()
+
+	err := uc.Generate(context.Background())
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(cfg.DashboardFilePath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "owner/repo2")
+	assert.Contains(t, string(content), "| 100 | 100 ★ |")   // No usable past value, so compared against 0
+	assert.Contains(t, string(content), "| 300 (stale) | 10 ★ |") // Carried forward value is flagged
+
+	storer.AssertExpectations(t)
+}
+
 // isSameDate checks if two time.Time objects represent the same date (ignoring time).
 func isSameDate(a, b time.Time) bool {
 	return a.Year() == b.Year() && a.Month() == b.Month() && a.Day() == b.Day()
 }
+
+func TestUsecase_Update_IntradaySamples(t *testing.T) {
+	uc, fetcher, storer, cfg := setupTestUsecase(t)
+	cfg.IntradaySamples = true
+
+	repo1, _ := domain.NewRepository("owner/repo1", 100)
+	storer.On("LoadTargetRepos").Return(targets("owner/repo1"), nil).Once()
+	fetcher.On("FetchStars", mock.Anything, "owner/repo1").Return(repo1, nil).Once()
+	var merged []*domain.Repository
+	storer.On("Merge", mock.AnythingOfType("time.Time"), mock.AnythingOfType("[]*domain.Repository")).
+		Run(func(args mock.Arguments) { merged = args.Get(1).([]*domain.Repository) }).
+		Return(nil).Once()
+
+	require.NoError(t, uc.Update(context.Background()))
+
+	require.Len(t, merged, 1)
+	assert.False(t, merged[0].FetchedAt.IsZero())
+	assert.Equal(t, []domain.Sample{{FetchedAt: merged[0].FetchedAt, Stars: 100}}, merged[0].Samples)
+	storer.AssertExpectations(t)
+}
@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/yourname/go-trendboard/internal/infra/presenter"
+)
+
+// ValidateConfig checks that the configuration can be used by every command:
+// settings hold known values, output directories are writable, the dashboard
+// template parses and the repositories file is valid. All problems found are returned together.
+func (u *Usecase) ValidateConfig(ctx context.Context) error {
+	u.logger.Info("Validating configuration...")
+
+	var errs []error
+	if err := u.cfg.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	dirs := []struct{ key, dir string }{
+		{"data_dir_path", u.cfg.DataDirPath},
+		{"dashboard_file_path", filepath.Dir(u.cfg.DashboardFilePath)},
+		{"http_cache_dir", u.cfg.HTTPCacheDir},
+		{"index_cache_dir", u.cfg.IndexCacheDir},
+	}
+	if u.cfg.StorageBackend == "sqlite" {
+		dirs = append(dirs, struct{ key, dir string }{"sqlite_path", filepath.Dir(u.cfg.SQLitePath)})
+	}
+	for _, d := range dirs {
+		if d.dir == "" {
+			continue
+		}
+		if err := checkWritableDir(d.dir); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", d.key, err))
+		}
+	}
+
+	if err := presenter.Validate(u.cfg, u.logger); err != nil {
+		errs = append(errs, fmt.Errorf("dashboard: %w", err))
+	}
+
+	repoNames, err := u.storer.LoadTargetRepos()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("repos_file_path: %w", err))
+	}
+	for _, target := range repoNames {
+		if err := target.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("repos_file_path: %w", err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		u.logger.Error("Configuration is invalid", "problems", len(errs))
+		return err
+	}
+	u.logger.Info("Configuration is valid.")
+	return nil
+}
+
+// checkWritableDir checks that files can be created in dir. A directory that doesn't
+// exist yet is fine as long as its closest existing ancestor is writable, since it is
+// created on first use.
+func checkWritableDir(dir string) error {
+	existing := dir
+	for {
+		info, err := os.Stat(existing)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("'%s' is not a directory", existing)
+			}
+			break
+		}
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to access '%s': %w", existing, err)
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return fmt.Errorf("no existing parent directory for '%s'", dir)
+		}
+		existing = parent
+	}
+
+	f, err := os.CreateTemp(existing, ".trendboard-write-check-*")
+	if err != nil {
+		return fmt.Errorf("'%s' is not writable: %w", existing, err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
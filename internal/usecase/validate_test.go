@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yourname/go-trendboard/internal/infra/storage"
+)
+
+func TestUsecase_ValidateConfig(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		uc, _, storer, cfg := setupTestUsecase(t)
+		cfg.LogLevel = "info"
+		cfg.Fetcher = "rest"
+		cfg.GitHubTokenStrategy = "round-robin"
+		cfg.StorageBackend = "file"
+		cfg.ArchiveGranularity = "daily"
+		cfg.DataDirPath = filepath.Join(t.TempDir(), "not", "yet", "created")
+		storer.On("LoadTargetRepos").Return(targets("owner/repo"), nil).Once()
+
+		require.NoError(t, uc.ValidateConfig(context.Background()))
+		storer.AssertExpectations(t)
+	})
+
+	t.Run("Reports every problem", func(t *testing.T) {
+		uc, _, storer, cfg := setupTestUsecase(t)
+		cfg.LogLevel = "info"
+		cfg.Fetcher = "soap"
+		cfg.GitHubTokenStrategy = "round-robin"
+		cfg.StorageBackend = "file"
+		cfg.ArchiveGranularity = "daily"
+		cfg.DashboardFormat = "html"
+		require.NoError(t, os.WriteFile(cfg.DashboardTemplatePath, []byte("{{ .Broken "), 0644))
+		notADir := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(notADir, nil, 0644))
+		cfg.DataDirPath = notADir
+		storer.On("LoadTargetRepos").Return(targets("owner/repo", "invalid"), nil).Once()
+
+		err := uc.ValidateConfig(context.Background())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown fetcher 'soap'")
+		assert.Contains(t, err.Error(), "data_dir_path:")
+		assert.Contains(t, err.Error(), "failed to parse HTML template")
+		assert.Contains(t, err.Error(), "invalid repository full name format: invalid")
+	})
+
+	t.Run("Missing repos file", func(t *testing.T) {
+		uc, _, storer, cfg := setupTestUsecase(t)
+		cfg.LogLevel = "info"
+		cfg.Fetcher = "rest"
+		cfg.GitHubTokenStrategy = "round-robin"
+		cfg.StorageBackend = "file"
+		cfg.ArchiveGranularity = "daily"
+		cfg.DataDirPath = t.TempDir()
+		storer.On("LoadTargetRepos").Return(nil, storage.ErrReposConfigNotFound).Once()
+
+		err := uc.ValidateConfig(context.Background())
+		require.ErrorIs(t, err, storage.ErrReposConfigNotFound)
+	})
+}
@@ -0,0 +1,20 @@
+// Package version reports the version of go-trendboard.
+package version
+
+import "runtime/debug"
+
+// Version is the version of go-trendboard. Release builds set it with
+// -ldflags "-X github.com/yourname/go-trendboard/internal/version.Version=v1.2.3".
+var Version string
+
+// Current returns the version of the running binary: Version if it was set at build time, and the
+// module version recorded by the Go toolchain otherwise, e.g. for binaries built with go install.
+func Current() string {
+	if Version != "" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}